@@ -3,11 +3,16 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/gsow-swc/coc/pkg/cmd"
 	"github.com/gsow-swc/coc/pkg/cmd2"
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/http"
 	"github.com/gsow-swc/coc/pkg/log"
 	"github.com/gsow-swc/coc/pkg/query/request"
+	logrus "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
@@ -99,12 +104,16 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.IntFlag{
+							Name:  "top",
+							Usage: "Show the war strength of this many of the strongest members, which fetches each member's profile",
+						},
 					},
 				},
 				{
 					Name:        "members",
 					Usage:       "Retrieves a list of members of a clan",
-					Description: "Retrieves a list of members of a clan",
+					Description: "Retrieves a list of members of a clan, with their heroes, how rushed each base is for its town hall and their war strength estimate",
 					Action:      cmd2.ClanMembersGet,
 					Flags: []cli.Flag{
 						&cli.StringFlag{
@@ -144,6 +153,30 @@ var (
 						},
 					},
 				},
+				{
+					Name:        "summary",
+					Usage:       "Summarizes a clan's war record",
+					Description: "Summarizes a clan's record over its regular wars, from every page of the war log and the local archive, with win-loss-tie records over recent wars, streaks, and breakdowns by team size, opponent clan level and month",
+					Action:      cmd2.WarSummary,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.IntSliceFlag{
+							Name:    "windows",
+							Aliases: []string{"W"},
+							Usage:   "Numbers of recent wars to show records for, one flag for each window",
+							Value:   cli.NewIntSlice(10, 25, 50),
+						},
+					},
+				},
 				{
 					Name:        "current",
 					Usage:       "Retrieves information about the current war for a clan",
@@ -160,6 +193,11 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
 					},
 				},
 				{
@@ -178,6 +216,11 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
 					},
 				},
 				{
@@ -196,6 +239,11 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
 					},
 				},
 				{
@@ -214,6 +262,16 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.IntFlag{
+							Name:        "top",
+							Usage:       "Compare the war strength of this many of the strongest members of each clan",
+							DefaultText: "the whole roster",
+						},
 					},
 				},
 				{
@@ -232,6 +290,143 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+					},
+				},
+				{
+					Name:        "plan",
+					Usage:       "Plans the attacks on the non-cleared targets in the current war",
+					Description: "Assigns the clan's attackers to the opponent's bases using town hall and hero levels, so every base that hasn't been three starred is covered, and lists the calls",
+					Action:      cmd2.WarPlan,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
+							Name:  "rule",
+							Usage: "The rule for assigning targets: mirror, or top to keep the top of the map on the top of the map",
+							Value: "mirror",
+						},
+						&cli.IntFlag{
+							Name:  "top",
+							Usage: "The number of bases at the top of the map for the top rule",
+							Value: 10,
+						},
+						&cli.StringFlag{
+							Name:    "format",
+							Aliases: []string{"f"},
+							Usage:   "The output format: table, or text for a call list to post in the clan chat",
+							Value:   "table",
+						},
+					},
+				},
+				{
+					Name:        "forecast",
+					Usage:       "Projects the result of the current war",
+					Description: "Projects the final stars and destruction of the current war, and the chance of winning, if the attacks left are made as well as the clan's archived attacks",
+					Action:      cmd2.WarForecast,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only use archived wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only use archived wars that started on or before this date (YYYY-MM-DD)",
+						},
+						&cli.IntFlag{
+							Name:  "runs",
+							Usage: "The number of simulated wars",
+							Value: 10000,
+						},
+					},
+				},
+				{
+					Name:        "missed",
+					Usage:       "Lists the members who missed attacks in regular wars",
+					Description: "Lists the members who didn't use both attacks in each regular war that has ended, from the archive and the current war, with their missed attacks and strikes over all the wars",
+					Action:      cmd2.WarMissed,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only include wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only include wars that started on or before this date (YYYY-MM-DD)",
+						},
+						&cli.IntFlag{
+							Name:  "strikes",
+							Usage: "Flag members with this many strikes, or 0 to never flag (default 3, or missed.strike_limit in the configuration)",
+						},
+						&cli.StringFlag{
+							Name:  "strike-per",
+							Usage: "Give a strike for each war with a missed attack or for each missed attack: war or attack (default war, or missed.strike_per in the configuration)",
+						},
+					},
+				},
+				{
+					Name:        "scout",
+					Usage:       "Retrieves a scouting report on the opponent in the current war",
+					Description: "Retrieves a scouting report on the opponent in the current war, with their war record and recent wars if the war log is public, their town hall distribution, and each member's heroes, rush score, war stars and CWL stars",
+					Action:      cmd2.WarScout,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
 					},
 				},
 			},
@@ -257,12 +452,17 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
-						&cli.IntFlag{
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
 							Name:        "round",
 							Aliases:     []string{"r"},
-							Usage:       "The CWL round",
-							Value:       0,
-							DefaultText: "the current war",
+							Usage:       "The CWL round: current, prep, last or a round number",
+							Value:       "current",
+							DefaultText: "current",
 						},
 					},
 				},
@@ -282,12 +482,17 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
-						&cli.IntFlag{
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
 							Name:        "round",
 							Aliases:     []string{"r"},
-							Usage:       "The CWL round",
-							Value:       0,
-							DefaultText: "the current war",
+							Usage:       "The CWL round: current, prep, last or a round number",
+							Value:       "current",
+							DefaultText: "current",
 						},
 					},
 				},
@@ -307,12 +512,17 @@ var (
 							Aliases: []string{"n"},
 							Usage:   "The name of the clan",
 						},
-						&cli.IntFlag{
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
 							Name:        "round",
 							Aliases:     []string{"r"},
-							Usage:       "The CWL round",
-							Value:       0,
-							DefaultText: "the current war",
+							Usage:       "The CWL round: current, prep, last or a round number",
+							Value:       "current",
+							DefaultText: "current",
 						},
 					},
 				},
@@ -321,6 +531,108 @@ var (
 					Usage:       "Retrieves the roster in a Clan War League war",
 					Description: "Retrieves the roster in a Clan War League war",
 					Action:      cmd.CwlRoster,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "war",
+							Aliases: []string{"w"},
+							Usage:   "The ID of a war in the local archive, as listed by `coc archive ls`",
+						},
+						&cli.StringFlag{
+							Name:        "round",
+							Aliases:     []string{"r"},
+							Usage:       "The CWL round: current, prep, last or a round number",
+							Value:       "current",
+							DefaultText: "current",
+						},
+					},
+				},
+				{
+					Name:        "season",
+					Usage:       "Retrieves the standings of the Clan War League group",
+					Description: "Retrieves the standings of the clans in the Clan War League group over all rounds, ranked by stars and then destruction, with the projected promotions and demotions and the clan's result in each round",
+					Action:      cmd2.CwlSeason,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+					},
+				},
+				{
+					Name:        "bonus",
+					Usage:       "Proposes the recipients of the CWL bonus medals",
+					Description: "Scores each member over the rounds of the Clan War League season on stars, destruction, attacks used, town hall levels hit above or below their own and defenses that weren't three starred, ranks the members and proposes the recipients of the league's bonus medal rewards. Weights not given as options are read from cwl_bonus in the configuration.",
+					Action:      cmd2.CwlBonus,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.IntFlag{
+							Name:        "count",
+							Usage:       "Number of bonus medal rewards to propose",
+							DefaultText: "the number for the clan's war league and wars won",
+						},
+						&cli.StringFlag{
+							Name:        "format",
+							Aliases:     []string{"f"},
+							Usage:       "Output format: table or csv",
+							Value:       "table",
+						},
+						&cli.Float64Flag{
+							Name:        "stars-weight",
+							Usage:       "Score for each star earned",
+							DefaultText: "2",
+						},
+						&cli.Float64Flag{
+							Name:        "destruction-weight",
+							Usage:       "Score for each 100% of destruction",
+							DefaultText: "1",
+						},
+						&cli.Float64Flag{
+							Name:        "attacks-weight",
+							Usage:       "Score for each attack used",
+							DefaultText: "1",
+						},
+						&cli.Float64Flag{
+							Name:        "th-weight",
+							Usage:       "Score for each town hall level hit above the member's own, taken away for hitting below",
+							DefaultText: "1",
+						},
+						&cli.Float64Flag{
+							Name:        "defense-weight",
+							Usage:       "Score for each defense that wasn't three starred",
+							DefaultText: "1",
+						},
+					},
+				},
+				{
+					Name:        "plan",
+					Usage:       "Recommends a lineup for a CWL round",
+					Description: "Recommends a lineup for a round of the Clan War League from the clan's roster, using each member's town hall and heroes, their attacks in earlier rounds and the lineup rules. Rules not given as options are read from cwl_plan in the configuration.",
+					Action:      cmd2.CwlPlan,
 					Flags: []cli.Flag{
 						&cli.StringFlag{
 							Name:    "clan",
@@ -335,14 +647,418 @@ var (
 						&cli.IntFlag{
 							Name:        "round",
 							Aliases:     []string{"r"},
-							Usage:       "The CWL round",
+							Usage:       "The CWL round to plan",
+							DefaultText: "the next round to start",
+						},
+						&cli.IntFlag{
+							Name:        "size",
+							Usage:       "Number of members in the lineup",
+							DefaultText: "the size of the clan's wars in the group, or 15",
+						},
+						&cli.IntFlag{
+							Name:  "min-wars",
+							Usage: "Minimum number of wars for each member over the season",
+						},
+						&cli.StringSliceFlag{
+							Name:  "always-in",
+							Usage: "Tag or name of a member who is always in the lineup",
+						},
+						&cli.StringSliceFlag{
+							Name:  "bench",
+							Usage: "Tag or name of a member who is left out of the lineup",
+						},
+					},
+				},
+				{
+					Name:        "missed",
+					Usage:       "Lists the members who missed attacks in CWL wars",
+					Description: "Lists the members who didn't use their attack in each CWL war that has ended, from the archive and every round of the current league, with their missed attacks and strikes over all the wars",
+					Action:      cmd2.CwlMissed,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only include wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only include wars that started on or before this date (YYYY-MM-DD)",
+						},
+						&cli.IntFlag{
+							Name:  "strikes",
+							Usage: "Flag members with this many strikes, or 0 to never flag (default 3, or missed.strike_limit in the configuration)",
+						},
+						&cli.StringFlag{
+							Name:  "strike-per",
+							Usage: "Give a strike for each war with a missed attack or for each missed attack: war or attack (default war, or missed.strike_per in the configuration)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "raid",
+			Usage:       "Retrieve information about Clan Capital raid weekends",
+			Description: "Retrieves information about Clan Capital raid weekends",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "ls",
+					Usage:       "Retrieves the list of raid weekends for a clan",
+					Description: "Retrieves the list of raid weekends for a clan",
+					Action:      cmd2.RaidList,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.IntFlag{
+							Name:    "limit",
+							Aliases: []string{"l"},
+							Usage:   "The number of raid weekends to list",
+							Value:   10,
+						},
+					},
+				},
+				{
+					Name:        "get",
+					Usage:       "Retrieves the members' attacks and capital gold in a raid weekend",
+					Description: "Retrieves who used all their raid attacks, who missed some, and the capital gold looted by each member",
+					Action:      cmd2.RaidGet,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.IntFlag{
+							Name:        "season",
+							Aliases:     []string{"s"},
+							Usage:       "The raid weekend, counting back from the most recent one",
 							Value:       0,
-							DefaultText: "the current war",
+							DefaultText: "the most recent raid weekend",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "archive",
+			Usage:       "Save wars in the local archive and list them",
+			Description: "Saves wars in the local archive, so their attacks remain available after the war log forgets them",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "save",
+					Usage:       "Saves the current war and Clan War League wars for a clan",
+					Description: "Saves the current war and Clan War League wars for a clan, keeping the latest snapshot of each war",
+					Action:      cmd2.ArchiveSave,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+					},
+				},
+				{
+					Name:        "ls",
+					Usage:       "Lists the wars for a clan in the archive",
+					Description: "Lists the wars for a clan in the archive",
+					Action:      cmd2.ArchiveList,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only include wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only include wars that started on or before this date (YYYY-MM-DD)",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "daemon",
+			Usage:       "Polls and archives wars, members and players on a schedule",
+			Description: "Polls the current war, Clan War League wars, members and players of the configured clans, saving them in the local archive until stopped with SIGINT or SIGTERM",
+			Action:      cmd2.Daemon,
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:        "clan",
+					Aliases:     []string{"c"},
+					Usage:       "The ID of a clan to poll, one flag for each clan",
+					DefaultText: "the clans in the configuration",
+				},
+				&cli.DurationFlag{
+					Name:  "members-interval",
+					Usage: "How often to poll the members of each clan",
+					Value: time.Hour,
+				},
+				&cli.DurationFlag{
+					Name:  "players-interval",
+					Usage: "How often to poll the profiles of the members of each clan",
+					Value: 12 * time.Hour,
+				},
+			},
+		},
+		{
+			Name:        "members",
+			Usage:       "Retrieve the membership history of a clan from the local archive",
+			Description: "Retrieves the membership history of a clan from the member snapshots in the local archive",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "history",
+					Usage:       "Retrieves the joins, leaves and role changes of the clan's members",
+					Description: "Retrieves the joins, leaves, promotions, demotions, name changes and town hall upgrades of the clan's members, and the total time each player has spent in the clan",
+					Action:      cmd2.MembersHistory,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "player",
+							Aliases: []string{"p"},
+							Usage:   "Only show the history of the player with this tag or name",
+						},
+						&cli.StringFlag{
+							Name:  "since",
+							Usage: "Only show events on or after this date (YYYY-MM-DD)",
 						},
 					},
 				},
 			},
 		},
+		{
+			Name:        "donations",
+			Usage:       "Retrieve the troops donated and received by the clan's members",
+			Description: "Retrieves the troops donated and received by each member in the current season, ranked by troops donated, and flags members whose ratio of donated to received troops is below the leech ratio. Earlier seasons are totalled from the member snapshots in the local archive.",
+			Action:      cmd2.Donations,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "clan",
+					Aliases: []string{"c"},
+					Usage:   "The ID of the clan",
+				},
+				&cli.StringFlag{
+					Name:    "name",
+					Aliases: []string{"n"},
+					Usage:   "The name of the clan",
+				},
+				&cli.Float64Flag{
+					Name:  "leech-ratio",
+					Usage: "Flag members who donated fewer than this many troops for each troop received (default 0.5, or donations.leech_ratio in the configuration)",
+				},
+				&cli.IntFlag{
+					Name:    "season",
+					Aliases: []string{"s"},
+					Usage:   "Number of seasons before the current one, totalled from the archive",
+				},
+				&cli.BoolFlag{
+					Name:  "seasons",
+					Usage: "List the seasons in the archive and when each season reset was detected",
+				},
+			},
+		},
+		{
+			Name:        "progress",
+			Usage:       "Retrieve the upgrades made by a player or by the clan's members",
+			Description: "Retrieves the upgrade timeline of a player, with the levels gained each week and how close each kind of unit is to max, or a summary of who upgraded what in the clan over the last week. Both are built from the player snapshots in the local archive.",
+			Action:      cmd2.Progress,
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "player",
+					Aliases: []string{"p"},
+					Usage:   "The tag of the player",
+				},
+				&cli.StringFlag{
+					Name:    "clan",
+					Aliases: []string{"c"},
+					Usage:   "The ID of the clan",
+				},
+				&cli.StringFlag{
+					Name:    "name",
+					Aliases: []string{"n"},
+					Usage:   "The name of the clan",
+				},
+				&cli.StringFlag{
+					Name:  "since",
+					Usage: "Only show upgrades on or after this date (YYYY-MM-DD), defaults to a week ago for a clan",
+				},
+			},
+		},
+		{
+			Name:        "stats",
+			Usage:       "Retrieve statistics over the wars in the local archive",
+			Description: "Retrieves statistics over the wars in the local archive",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "members",
+					Usage:       "Retrieves the attack statistics for each member",
+					Description: "Retrieves the hit rate, average stars and destruction, attacks used and performance against equal, higher and lower town halls for each member",
+					Action:      cmd2.StatsMembers,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only include wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only include wars that started on or before this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:    "sort",
+							Aliases: []string{"s"},
+							Usage:   "Column to sort by: name, th, wars, attacks, missed, hitrate, stars, destruction, equal, higher or lower",
+							Value:   "hitrate",
+						},
+						&cli.BoolFlag{
+							Name:    "reverse",
+							Aliases: []string{"r"},
+							Usage:   "Reverse the sort order",
+						},
+					},
+				},
+				{
+					Name:        "defense",
+					Usage:       "Retrieves the defense statistics for each member",
+					Description: "Retrieves the attacks received, hold rate (attacks held to two stars or fewer) and average stars and destruction conceded for each member, broken down by the town hall of the attacker",
+					Action:      cmd2.StatsDefense,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "clan",
+							Aliases: []string{"c"},
+							Usage:   "The ID of the clan",
+						},
+						&cli.StringFlag{
+							Name:    "name",
+							Aliases: []string{"n"},
+							Usage:   "The name of the clan",
+						},
+						&cli.StringFlag{
+							Name:  "from",
+							Usage: "Only include wars that started on or after this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:  "to",
+							Usage: "Only include wars that started on or before this date (YYYY-MM-DD)",
+						},
+						&cli.StringFlag{
+							Name:    "sort",
+							Aliases: []string{"s"},
+							Usage:   "Column to sort by: name, th, wars, attacks, holdrate, stars or destruction",
+							Value:   "holdrate",
+						},
+						&cli.BoolFlag{
+							Name:    "reverse",
+							Aliases: []string{"r"},
+							Usage:   "Reverse the sort order",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "goldpass",
+			Usage:       "Retrieves the current Gold Pass season",
+			Description: "Retrieves the start and end of the current Gold Pass season and the time remaining",
+			Action:      cmd2.GoldPass,
+		},
+		{
+			Name:        "player",
+			Usage:       "Retrieve information about a player",
+			Description: "Retrieves information about a player",
+			Subcommands: []*cli.Command{
+				{
+					Name:        "get",
+					Usage:       "Gets the profile of a player",
+					Description: "Gets the profile of a player, including heroes, equipment, pets, siege machines, troops and spells, and how rushed the base is for its town hall",
+					Action:      cmd2.PlayerGet,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "player",
+							Aliases: []string{"p"},
+							Usage:   "The tag of the player",
+						},
+					},
+				},
+				{
+					Name:        "verify",
+					Usage:       "Verifies that an API token belongs to a player",
+					Description: "Verifies the API token shown in a player's in-game settings, proving the player owns the account",
+					Action:      cmd2.PlayerVerify,
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:    "player",
+							Aliases: []string{"p"},
+							Usage:   "The tag of the player",
+						},
+						&cli.StringFlag{
+							Name:  "token",
+							Usage: "The API token from the player's settings",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:        "raw",
+			Usage:       "Retrieves the unparsed response for an API path",
+			Description: "Retrieves the unparsed response for an API path, such as /clans/#2PP/currentwar",
+			ArgsUsage:   "<path>",
+			Action:      cmd2.Raw,
+		},
 		{
 			Name:   "labels",
 			Usage:  "Retrieve information about labels in Clash of Clans",
@@ -358,15 +1074,15 @@ var (
 			Usage:  "Retrieve information about locations in Clash of Clans",
 			Action: locations,
 		},
-		{
-			Name:   "players",
-			Usage:  "Retrieve information about players in Clash of Clans",
-			Action: players,
-		},
 	}
 
 	// flags are the set of flags supported by the CoC application
 	flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			EnvVars: []string{"COC_CONFIG"},
+			Usage:   "YAML or JSON configuration file",
+		},
 		&cli.StringFlag{
 			Name:        "token",
 			Aliases:     []string{"t"},
@@ -380,6 +1096,26 @@ var (
 			Usage: "Log level to be used when logging messages",
 			Value: "Warn",
 		},
+		&cli.StringFlag{
+			Name:        "archive",
+			EnvVars:     []string{"COC_ARCHIVE"},
+			Usage:       "Path of the local archive of wars and other snapshots",
+			DefaultText: "~/.coc/archive.db",
+		},
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Log fields in responses that aren't modelled yet (requires the Debug log level)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-cache",
+			Usage: "Always fetch from the Clash of Clans REST server instead of reusing cached responses",
+		},
+		&cli.StringFlag{
+			Name:        "cache-dir",
+			EnvVars:     []string{"COC_CACHE_DIR"},
+			Usage:       "Directory used to cache responses from the Clash of Clans REST server",
+			DefaultText: "the user cache directory",
+		},
 	}
 )
 
@@ -398,9 +1134,26 @@ func locations(ctx *cli.Context) error {
 	return nil
 }
 
-func players(ctx *cli.Context) error {
-	fmt.Printf("Hello players %q", ctx.Args().Get(0))
-	return nil
+// initializeCache sets up the on-disk cache of responses, falling back to an in-memory cache if the
+// cache directory can't be used.
+func initializeCache(dir string) {
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			logrus.Warn("unable to find the user cache directory: ", err)
+			http.SetCache(http.NewMemoryCache())
+			return
+		}
+		dir = filepath.Join(userDir, appName)
+	}
+
+	fc, err := http.NewFileCache(dir)
+	if err != nil {
+		logrus.Warn("unable to use cache directory ", dir, ": ", err)
+		http.SetCache(http.NewMemoryCache())
+		return
+	}
+	http.SetCache(fc)
 }
 
 /*
@@ -438,6 +1191,14 @@ func main() {
 		Usage:    usage,
 		Version:  Version + "+" + Revision + Build,
 		Before: func(c *cli.Context) error {
+			// Load the configuration
+			if file := c.String("config"); file != "" {
+				if err := config.Load(file); err != nil {
+					fmt.Println(err)
+					return err
+				}
+			}
+
 			// Initialize the logging
 			l := c.String("log")
 			logLevel := log.GetLogLevel(l)
@@ -449,6 +1210,13 @@ func main() {
 				request.SetToken(token)
 			}
 
+			request.SetStrict(c.Bool("strict"))
+
+			// Set up the response cache
+			if !c.Bool("no-cache") {
+				initializeCache(c.String("cache-dir"))
+			}
+
 			return nil
 		},
 	}
@@ -0,0 +1,46 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// lockTimeout is how long to wait for another process, such as the daemon, to release the archive
+	lockTimeout = 10 * time.Second
+)
+
+// Archive is a local store of snapshots retrieved from Clash of Clans, kept so that data the API
+// forgets, such as the attacks in past wars, remains available.
+type Archive struct {
+	db *bolt.DB
+}
+
+// DefaultPath returns the path of the archive used when no other path is configured.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".coc", "archive.db"), nil
+}
+
+// Open opens the archive at the given path, creating it if it doesn't exist.
+func Open(path string) (*Archive, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: lockTimeout})
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{db: db}, nil
+}
+
+// Close closes the archive, releasing it for use by other processes.
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
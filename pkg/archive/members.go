@@ -0,0 +1,44 @@
+package archive
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+var (
+	// membersBucket holds the snapshots of clan members, keyed by clan tag and the time they were saved
+	membersBucket = []byte("members")
+)
+
+// Members is a snapshot of the members of a clan.
+type Members struct {
+	ClanTag string                // Tag of the clan
+	Saved   time.Time             // Time the snapshot was saved
+	Members []response.ClanMember // Members of the clan
+}
+
+// PutMembers saves a snapshot of the members of a clan, unless the members are unchanged since the
+// latest snapshot.  It returns true if the snapshot was saved.
+func (a *Archive) PutMembers(m Members) (bool, error) {
+	if m.Saved.IsZero() {
+		m.Saved = time.Now()
+	}
+	return a.putSnapshot(membersBucket, m.ClanTag, m.Saved, m.Members)
+}
+
+// Members returns the snapshots of the members of a clan saved within the given times, oldest first.
+// A zero time leaves that end of the range open.
+func (a *Archive) Members(clanTag string, from time.Time, to time.Time) ([]Members, error) {
+	var snapshots []Members
+	err := a.snapshots(membersBucket, clanTag, from, to, func(t time.Time, data []byte) error {
+		m := Members{ClanTag: response.NormalizeTag(clanTag), Saved: t}
+		if err := json.Unmarshal(data, &m.Members); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, m)
+		return nil
+	})
+	return snapshots, err
+}
@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+var (
+	// playersBucket holds the snapshots of players, keyed by player tag and the time they were saved
+	playersBucket = []byte("players")
+)
+
+// Player is a snapshot of a player.
+type Player struct {
+	Saved  time.Time       // Time the snapshot was saved
+	Player response.Player // The player
+}
+
+// PutPlayer saves a snapshot of a player, unless the player is unchanged since the latest snapshot.
+// It returns true if the snapshot was saved.
+func (a *Archive) PutPlayer(p Player) (bool, error) {
+	if p.Saved.IsZero() {
+		p.Saved = time.Now()
+	}
+	return a.putSnapshot(playersBucket, p.Player.Tag, p.Saved, p.Player)
+}
+
+// Players returns the snapshots of a player saved within the given times, oldest first.  A zero time
+// leaves that end of the range open.
+func (a *Archive) Players(tag string, from time.Time, to time.Time) ([]Player, error) {
+	var snapshots []Player
+	err := a.snapshots(playersBucket, tag, from, to, func(t time.Time, data []byte) error {
+		p := Player{Saved: t}
+		if err := json.Unmarshal(data, &p.Player); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, p)
+		return nil
+	})
+	return snapshots, err
+}
@@ -0,0 +1,84 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// keyTimeLayout is the layout of the time in the keys of snapshots, which sorts chronologically
+	keyTimeLayout = "20060102T150405Z"
+)
+
+// snapshotKey returns the key used to store a snapshot of the object with the given tag.
+func snapshotKey(tag string, t time.Time) []byte {
+	return []byte(tag + "|" + t.UTC().Format(keyTimeLayout))
+}
+
+// putSnapshot saves a snapshot of an object in the bucket, unless it is the same as the latest snapshot
+// of the object.  The saved time is not part of the data compared.  It returns true if the snapshot was saved.
+func (a *Archive) putSnapshot(name []byte, tag string, t time.Time, data interface{}) (bool, error) {
+	tag = response.NormalizeTag(tag)
+	b, err := json.Marshal(data)
+	if err != nil {
+		return false, err
+	}
+
+	saved := false
+	err = a.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(name)
+		if err != nil {
+			return err
+		}
+
+		// Find the latest snapshot, which is the key before the first key past those for the tag
+		prefix := []byte(tag + "|")
+		c := bucket.Cursor()
+		k, _ := c.Seek([]byte(tag + "}"))
+		var last []byte
+		if k == nil {
+			k, last = c.Last()
+		} else {
+			k, last = c.Prev()
+		}
+		if k != nil && bytes.HasPrefix(k, prefix) && bytes.Equal(last, b) {
+			return nil
+		}
+
+		saved = true
+		return bucket.Put(snapshotKey(tag, t), b)
+	})
+	return saved, err
+}
+
+// snapshots calls fn with the time and data of each snapshot of the object with the given tag that was
+// saved within the given times, oldest first.  A zero time leaves that end of the range open.
+func (a *Archive) snapshots(name []byte, tag string, from time.Time, to time.Time, fn func(t time.Time, data []byte) error) error {
+	tag = response.NormalizeTag(tag)
+	return a.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(name)
+		if bucket == nil {
+			return nil
+		}
+
+		prefix := []byte(tag + "|")
+		c := bucket.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			t, err := time.Parse(keyTimeLayout, string(k[len(prefix):]))
+			if err != nil {
+				continue
+			}
+			if (!from.IsZero() && t.Before(from)) || (!to.IsZero() && !t.Before(to)) {
+				continue
+			}
+			if err := fn(t, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,208 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// warsBucket holds the war snapshots, keyed by clan tag and preparation start time
+	warsBucket = []byte("wars")
+)
+
+// War is a snapshot of a regular war or a Clan War League war for a clan.
+type War struct {
+	ClanTag   string                     `json:"clanTag"`             // Tag of the clan the war was saved for
+	WarTag    string                     `json:"warTag,omitempty"`    // Tag of a Clan War League war
+	Saved     time.Time                  `json:"saved"`               // Time the snapshot was saved
+	War       *response.ClanWar          `json:"war,omitempty"`       // A regular war
+	LeagueWar *response.ClanWarLeagueWar `json:"leagueWar,omitempty"` // A Clan War League war
+}
+
+// ID returns the identifier of the war, which is its preparation start time.
+func (w War) ID() string {
+	if w.LeagueWar != nil {
+		return w.LeagueWar.PreparationStartTime
+	}
+	if w.War != nil {
+		return w.War.PreparationStartTime
+	}
+	return ""
+}
+
+// IsLeagueWar returns true if the war is a Clan War League war.
+func (w War) IsLeagueWar() bool {
+	return w.LeagueWar != nil
+}
+
+// State returns the state of the war when the snapshot was saved.
+func (w War) State() string {
+	if w.LeagueWar != nil {
+		return w.LeagueWar.State
+	}
+	if w.War != nil {
+		return w.War.State
+	}
+	return ""
+}
+
+// PreparationStart returns the time preparation day started for the war.
+func (w War) PreparationStart() time.Time {
+	t, _ := response.ParseTime(w.ID())
+	return t
+}
+
+// AttacksPerMember returns the number of attacks each member has in the war.
+func (w War) AttacksPerMember() int {
+	if w.LeagueWar != nil {
+		return 1
+	}
+	return 2
+}
+
+// ClanWar returns the war with the clan the snapshot was saved for as the clan, and the other clan as
+// the opponent.  Wars may list the clans in either order, so they are swapped if required.
+func (w War) ClanWar() response.ClanWar {
+	var cw response.ClanWar
+	switch {
+	case w.War != nil:
+		cw = *w.War
+	case w.LeagueWar != nil:
+		lw := w.LeagueWar
+		cw = response.ClanWar{
+			State:                lw.State,
+			TeamSize:             lw.TeamSize,
+			PreparationStartTime: lw.PreparationStartTime,
+			StartTime:            lw.StartTime,
+			EndTime:              lw.EndTime,
+			Clan:                 lw.Clan,
+			Opponent:             lw.Opponent,
+		}
+	default:
+		return cw
+	}
+	tag := response.NormalizeTag(w.ClanTag)
+	if cw.Clan.Tag != tag && cw.Opponent.Tag == tag {
+		cw.Clan, cw.Opponent = cw.Opponent, cw.Clan
+	}
+
+	// Snapshots of the current war and league wars have no result, so work it out once the war is over
+	if cw.State == "warEnded" {
+		switch {
+		case cw.Clan.Stars > cw.Opponent.Stars:
+			cw.Result = "win"
+		case cw.Clan.Stars < cw.Opponent.Stars:
+			cw.Result = "lose"
+		case cw.Clan.DestructionPercentage > cw.Opponent.DestructionPercentage:
+			cw.Result = "win"
+		case cw.Clan.DestructionPercentage < cw.Opponent.DestructionPercentage:
+			cw.Result = "lose"
+		default:
+			cw.Result = "tie"
+		}
+	}
+	return cw
+}
+
+// warKey returns the key used to store a war.  The tag is normalized so it matches however it was typed.
+func warKey(clanTag string, id string) []byte {
+	return []byte(response.NormalizeTag(clanTag) + "|" + id)
+}
+
+// warStates are the states of a war, in the order they occur
+var warStates = map[string]int{
+	"preparation": 1,
+	"inWar":       2,
+	"warEnded":    3,
+}
+
+// PutWar saves a snapshot of a war.  Only one snapshot is kept for each war, so the snapshot replaces an
+// earlier one in the same or an earlier state, but never a snapshot taken later in the war.  It returns
+// true if the snapshot was saved.
+func (a *Archive) PutWar(w War) (bool, error) {
+	if w.ClanTag == "" || w.ID() == "" {
+		return false, fmt.Errorf("war snapshot has no clan tag or preparation start time")
+	}
+	if w.Saved.IsZero() {
+		w.Saved = time.Now()
+	}
+	w.ClanTag = response.NormalizeTag(w.ClanTag)
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		return false, err
+	}
+	saved := false
+	err = a.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(warsBucket)
+		if err != nil {
+			return err
+		}
+
+		// Don't replace a snapshot taken later in the war
+		key := warKey(w.ClanTag, w.ID())
+		if v := bucket.Get(key); v != nil {
+			var old War
+			if err := json.Unmarshal(v, &old); err == nil && warStates[old.State()] > warStates[w.State()] {
+				return nil
+			}
+		}
+
+		saved = true
+		return bucket.Put(key, b)
+	})
+	return saved, err
+}
+
+// War returns the saved war for a clan with the given identifier.  It returns false if there is no such war.
+func (a *Archive) War(clanTag string, id string) (War, bool, error) {
+	var w War
+	found := false
+	err := a.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(warsBucket)
+		if bucket == nil {
+			return nil
+		}
+		v := bucket.Get(warKey(clanTag, id))
+		if v == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(v, &w)
+	})
+	return w, found, err
+}
+
+// Wars returns the saved wars for a clan whose preparation started within the given times, oldest first.
+// A zero time leaves that end of the range open.
+func (a *Archive) Wars(clanTag string, from time.Time, to time.Time) ([]War, error) {
+	var wars []War
+	err := a.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(warsBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		// Keys sort by preparation start time within a clan
+		prefix := warKey(clanTag, "")
+		c := bucket.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var w War
+			if err := json.Unmarshal(v, &w); err != nil {
+				return err
+			}
+			start := w.PreparationStart()
+			if (!from.IsZero() && start.Before(from)) || (!to.IsZero() && !start.Before(to)) {
+				continue
+			}
+			wars = append(wars, w)
+		}
+		return nil
+	})
+	return wars, err
+}
@@ -0,0 +1,162 @@
+package archive
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// openTestArchive opens an empty archive in a temporary directory, returning a function that removes it
+func openTestArchive(t *testing.T) (*Archive, func()) {
+	dir, err := ioutil.TempDir("", "coc-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := Open(filepath.Join(dir, "archive.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return a, func() {
+		a.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// testWar returns a snapshot of a regular war in the given state
+func testWar(clanTag string, start string, state string, stars int) War {
+	return War{ClanTag: clanTag, War: &response.ClanWar{
+		State:                state,
+		PreparationStartTime: start,
+		Clan:                 response.ClanWarTeam{Tag: "#CLAN", Stars: stars},
+		Opponent:             response.ClanWarTeam{Tag: "#OPP"},
+	}}
+}
+
+func TestPutWar(t *testing.T) {
+	a, done := openTestArchive(t)
+	defer done()
+
+	const start = "20240101T080000.000Z"
+	tests := []struct {
+		name      string
+		war       War
+		saved     bool
+		wantState string
+		wantStars int
+	}{
+		{"first snapshot", testWar("#CLAN", start, "preparation", 0), true, "preparation", 0},
+		{"later state replaces", testWar("#CLAN", start, "inWar", 5), true, "inWar", 5},
+		{"same state replaces", testWar("clan", start, "inWar", 7), true, "inWar", 7},
+		{"ended replaces", testWar("#clan", start, "warEnded", 9), true, "warEnded", 9},
+		{"earlier state is ignored", testWar("#CLAN", start, "inWar", 3), false, "warEnded", 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved, err := a.PutWar(tt.war)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved != tt.saved {
+				t.Errorf("PutWar saved = %v, want %v", saved, tt.saved)
+			}
+			w, ok, err := a.War("#CLAN", start)
+			if err != nil || !ok {
+				t.Fatalf("War = %v, %v", ok, err)
+			}
+			if w.State() != tt.wantState || w.ClanWar().Clan.Stars != tt.wantStars {
+				t.Errorf("War state %s with %d stars, want %s with %d", w.State(), w.ClanWar().Clan.Stars, tt.wantState, tt.wantStars)
+			}
+		})
+	}
+
+	if _, err := a.PutWar(War{ClanTag: "#CLAN"}); err == nil {
+		t.Error("PutWar accepted a war without a preparation start time")
+	}
+}
+
+func TestWars(t *testing.T) {
+	a, done := openTestArchive(t)
+	defer done()
+
+	for _, w := range []War{
+		testWar("#CLAN", "20240103T080000.000Z", "warEnded", 1),
+		testWar("#CLAN", "20240101T080000.000Z", "warEnded", 2),
+		testWar("#CLAN2", "20240102T080000.000Z", "warEnded", 3),
+		testWar("#CLAN", "20240105T080000.000Z", "inWar", 4),
+	} {
+		if _, err := a.PutWar(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		tag      string
+		from, to time.Time
+		stars    []int
+	}{
+		{"all, oldest first", "#CLAN", time.Time{}, time.Time{}, []int{2, 1, 4}},
+		{"tag typed without #", "clan", time.Time{}, time.Time{}, []int{2, 1, 4}},
+		{"from", "#CLAN", day(2), time.Time{}, []int{1, 4}},
+		{"to is exclusive", "#CLAN", time.Time{}, day(3), []int{2}},
+		{"other clan", "#CLAN2", time.Time{}, time.Time{}, []int{3}},
+		{"unknown clan", "#NONE", time.Time{}, time.Time{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wars, err := a.Wars(tt.tag, tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var stars []int
+			for _, w := range wars {
+				stars = append(stars, w.ClanWar().Clan.Stars)
+			}
+			if len(stars) != len(tt.stars) {
+				t.Fatalf("Wars stars = %v, want %v", stars, tt.stars)
+			}
+			for i := range stars {
+				if stars[i] != tt.stars[i] {
+					t.Errorf("Wars stars = %v, want %v", stars, tt.stars)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestClanWar(t *testing.T) {
+	league := func(state string, stars int, opponentStars int) War {
+		return War{ClanTag: "clan", LeagueWar: &response.ClanWarLeagueWar{
+			State:    state,
+			Clan:     response.ClanWarTeam{Tag: "#OPP", Stars: opponentStars},
+			Opponent: response.ClanWarTeam{Tag: "#CLAN", Stars: stars},
+		}}
+	}
+	tests := []struct {
+		name       string
+		war        War
+		wantStars  int
+		wantResult string
+	}{
+		{"regular war won", testWar("#CLAN", "20240101T080000.000Z", "warEnded", 30), 30, "win"},
+		{"regular war in progress", testWar("#CLAN", "20240101T080000.000Z", "inWar", 30), 30, ""},
+		{"league war lost", league("warEnded", 20, 25), 20, "lose"},
+		{"league war tied", league("warEnded", 20, 20), 20, "tie"},
+		{"no war", War{ClanTag: "#CLAN"}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := tt.war.ClanWar()
+			if cw.Clan.Stars != tt.wantStars || cw.Result != tt.wantResult {
+				t.Errorf("ClanWar has %d stars and result %q, want %d and %q", cw.Clan.Stars, cw.Result, tt.wantStars, tt.wantResult)
+			}
+		})
+	}
+}
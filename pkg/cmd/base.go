@@ -76,7 +76,7 @@ func getClan(name string) (response.Clan, error) {
 // getTag gets the clan tag from the `clan` option or, if that is not present, from the `name` option
 func getTag(c *cli.Context) (string, error) {
 	// Get the tag of the clan
-	tag := c.String("clan")
+	tag := response.NormalizeTag(c.String("clan"))
 	if tag == "" {
 		name := c.String("name")
 		if name == "" {
@@ -25,7 +25,7 @@ func ClanList(c *cli.Context) error {
 	}
 
 	// Make sure at least one filter is specified
-	if req.Name == "" && req.WarFrequency == "" && req.LocationID == 0 && req.MinMembers == 0 && req.MaxMembers == 0 && (req.LabelIDs == nil || len(req.LabelIDs) == 0) && req.MinClanPoints == 0 && req.MaxMembers == 0 {
+	if req.Name == "" && req.WarFrequency == "" && req.LocationID == 0 && req.MinMembers == 0 && req.MaxMembers == 0 && (req.LabelIDs == nil || len(req.LabelIDs) == 0) && req.MinClanPoints == 0 && req.MinClanLevel == 0 {
 		cli.ShowCommandHelpAndExit(c, "ls", -1)
 	}
 
@@ -3,73 +3,177 @@ package cmd
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
+	"github.com/gsow-swc/coc/pkg/cmd2"
 	"github.com/gsow-swc/coc/pkg/query/request"
 	"github.com/gsow-swc/coc/pkg/query/response"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
 
-func getWar(tag string, r int) (response.ClanWarLeagueWar, error) {
+// getWar gets the clan's CWL war in the given round, which may be a round number or one of:
+//
+//	current - the war in progress, or if there is none the war in preparation, or else the last war
+//	prep    - the war in preparation
+//	last    - the last war that has ended
+func getWar(tag string, round string) (response.ClanWarLeagueWar, error) {
 	var war response.ClanWarLeagueWar
 
 	// Get the Clan War League information for the clan
-	req := request.ClanWarLeagueGroup{Tag: tag}
-	league, err := req.Get()
+	league, err := cmd2.GetLeagueGroup(tag)
 	if err != nil {
-		log.Error("failed to get the response")
+		return war, err
+	}
+	if len(league.Rounds) == 0 {
+		err := fmt.Errorf("the Clan War League group for clan %s has no rounds", tag)
 		fmt.Println(err)
 		return war, err
 	}
 
-	// Find the current war if a round isn't specified
-	if r == 0 {
-		for i, round := range league.Rounds {
-			r = i + 1
-			// War has not started
-			if round.WarTags[0] == "#0" {
-				r = i
+	war, err = findRoundWar(tag, round, len(league.Rounds), func(i int) (response.ClanWarLeagueWar, bool, error) {
+		return getRoundWar(league, tag, i)
+	})
+	if err != nil {
+		return war, err
+	}
+	return sortWar(war), nil
+}
+
+// findRoundWar finds the clan's war in the given round of a group with the given number of rounds, as
+// described for getWar.  The clan's war in the round with an index is retrieved with getRound, which
+// returns false if the round hasn't been drawn yet.
+func findRoundWar(tag string, round string, rounds int, getRound func(i int) (response.ClanWarLeagueWar, bool, error)) (response.ClanWarLeagueWar, error) {
+	var war response.ClanWarLeagueWar
+	var err error
+
+	switch round {
+	case "", "current", "prep", "last":
+		// Search backwards from the latest round, as only the last rounds are in preparation or in progress
+		var prep, ended *response.ClanWarLeagueWar
+		for i := rounds - 1; i >= 0; i-- {
+			w, ok, err := getRound(i)
+			if err != nil {
+				return war, err
+			}
+			if !ok {
+				continue
+			}
+			switch w.State {
+			case "inWar":
+				if round == "" || round == "current" {
+					return w, nil
+				}
+			case "preparation":
+				if prep == nil {
+					prep = &w
+				}
+				if round == "prep" {
+					return w, nil
+				}
+			case "warEnded":
+				if ended == nil {
+					ended = &w
+				}
+				if round == "last" {
+					return w, nil
+				}
+			}
+			// Earlier rounds have all ended, so there's nothing more to find once a war has ended
+			if ended != nil && (round == "" || round == "current") {
 				break
 			}
 		}
+		switch {
+		case round == "prep":
+			err = fmt.Errorf("no Clan War League war is in preparation for clan %s", tag)
+		case round == "last":
+			err = fmt.Errorf("no Clan War League war has ended for clan %s", tag)
+		case prep != nil:
+			return *prep, nil
+		case ended != nil:
+			return *ended, nil
+		default:
+			err = fmt.Errorf("no Clan War League rounds have been drawn for clan %s", tag)
+		}
+		fmt.Println(err)
+		return war, err
 	}
 
-	// Get the array index, ensuring it is 0..6 inclusive
-	i := r - 1
-	if i < 0 {
-		i = 0
-	} else if i > 6 {
-		i = 6
+	r, err := strconv.Atoi(round)
+	if err != nil {
+		err = fmt.Errorf("invalid round %q, expected current, prep, last or a round number", round)
+		fmt.Println(err)
+		return war, err
 	}
-
-	// Get the war tags
-	var warTags []string
-	for _, warTag := range league.Rounds[i].WarTags {
-		warTags = append(warTags, warTag)
+	if r < 1 || r > rounds {
+		err = fmt.Errorf("invalid round %d, the Clan War League group has %d rounds", r, rounds)
+		fmt.Println(err)
+		return war, err
+	}
+	w, ok, err := getRound(r - 1)
+	if err != nil {
+		return war, err
+	}
+	if !ok {
+		err = fmt.Errorf("round %d of the Clan War League has not been drawn yet", r)
+		fmt.Println(err)
+		return war, err
 	}
 
-	// Find the war for the clan we are searching for
-	for _, wt := range warTags {
+	return w, nil
+}
+
+// getRoundWar gets the clan's war in the round with the given index.  False is returned if the round
+// hasn't been drawn yet.
+func getRoundWar(league response.ClanWarLeagueGroup, tag string, i int) (response.ClanWarLeagueWar, bool, error) {
+	for _, wt := range league.Rounds[i].WarTags {
+		// Wars in rounds that haven't been drawn have a tag of #0
+		if wt == "#0" {
+			continue
+		}
 		req := request.ClanWarLeagueWar{Tag: wt}
-		war, err = req.Get()
+		war, err := req.Get()
 		if err != nil {
 			log.Error("failed to get the response")
 			fmt.Println(err)
-			return war, err
+			return war, false, err
 		}
 
-		// We found the clan we are searching for, so exit the loop
+		// We found the clan we are searching for
 		if war.Clan.Tag == tag || war.Opponent.Tag == tag {
-			break
+			return war, true, nil
 		}
 	}
+	return response.ClanWarLeagueWar{}, false, nil
+}
 
-	// Sort the members based on their map position
+// sortWar sorts the members of both clans in a war based on their map position
+func sortWar(war response.ClanWarLeagueWar) response.ClanWarLeagueWar {
 	sort.Slice(war.Clan.Members, func(i, j int) bool { return war.Clan.Members[i].MapPosition < war.Clan.Members[j].MapPosition })
 	sort.Slice(war.Opponent.Members, func(i, j int) bool { return war.Opponent.Members[i].MapPosition < war.Opponent.Members[j].MapPosition })
+	return war
+}
+
+// getCwlWar gets the CWL war for the round in the `round` option or, if the `war` option is present, the
+// CWL war with that identifier from the archive
+func getCwlWar(c *cli.Context, tag string) (response.ClanWarLeagueWar, error) {
+	if c.String("war") == "" {
+		return getWar(tag, c.String("round"))
+	}
+
+	w, err := cmd2.GetArchivedWar(c, tag)
+	if err != nil {
+		return response.ClanWarLeagueWar{}, err
+	}
+	if !w.IsLeagueWar() {
+		err := fmt.Errorf("war %s for clan %s is not a CWL war", c.String("war"), tag)
+		fmt.Println(err)
+		return response.ClanWarLeagueWar{}, err
+	}
 
-	return war, nil
+	return sortWar(*w.LeagueWar), nil
 }
 
 // CwlScoreboard gets summary data about the current CWL war
@@ -81,8 +185,7 @@ func CwlScoreboard(c *cli.Context) error {
 	}
 
 	// Get the war
-	round := c.Int("round")
-	war, err := getWar(tag, round)
+	war, err := getCwlWar(c, tag)
 	if err != nil {
 		return err
 	}
@@ -138,8 +241,7 @@ func CwlAttack(c *cli.Context) error {
 	}
 
 	// Get the war
-	round := c.Int("round")
-	war, err := getWar(tag, round)
+	war, err := getCwlWar(c, tag)
 	if err != nil {
 		return err
 	}
@@ -219,8 +321,7 @@ func CwlDefend(c *cli.Context) error {
 	}
 
 	// Get the war
-	round := c.Int("round")
-	war, err := getWar(tag, round)
+	war, err := getCwlWar(c, tag)
 	if err != nil {
 		return err
 	}
@@ -280,8 +381,7 @@ func CwlRoster(c *cli.Context) error {
 	}
 
 	// Get the war
-	round := c.Int("round")
-	war, err := getWar(tag, round)
+	war, err := getCwlWar(c, tag)
 	if err != nil {
 		return err
 	}
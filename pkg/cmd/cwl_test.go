@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+func TestFindRoundWar(t *testing.T) {
+	// getRounds returns a function that gets the war in each round from the given states, where an empty
+	// state is a round that hasn't been drawn, and counts the rounds that were retrieved
+	getRounds := func(states []string, fetched *int) func(i int) (response.ClanWarLeagueWar, bool, error) {
+		return func(i int) (response.ClanWarLeagueWar, bool, error) {
+			*fetched++
+			if states[i] == "" {
+				return response.ClanWarLeagueWar{}, false, nil
+			}
+			return response.ClanWarLeagueWar{State: states[i], TeamSize: i + 1}, true, nil
+		}
+	}
+	inProgress := []string{"warEnded", "warEnded", "inWar", "preparation", "", "", ""}
+	tests := []struct {
+		name      string
+		states    []string
+		round     string
+		wantRound int // Round number of the war found, or 0 for an error
+		wantFetch int
+	}{
+		{"current is the war in progress", inProgress, "current", 3, 5},
+		{"default is current", inProgress, "", 3, 5},
+		{"prep", inProgress, "prep", 4, 4},
+		{"last", inProgress, "last", 2, 6},
+		{"round number", inProgress, "1", 1, 1},
+		{"round not drawn", inProgress, "5", 0, 1},
+		{"round out of range", inProgress, "8", 0, 0},
+		{"invalid round", inProgress, "next", 0, 0},
+		{"current is in preparation on the first day", []string{"preparation", "", ""}, "current", 1, 3},
+		{"current is the last war once all have ended", []string{"warEnded", "warEnded", "warEnded"}, "current", 3, 1},
+		{"no war has ended", []string{"inWar", "preparation", ""}, "last", 0, 3},
+		{"no war in preparation", []string{"warEnded", "warEnded", "warEnded"}, "prep", 0, 3},
+		{"nothing drawn", []string{"", "", ""}, "current", 0, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetched := 0
+			w, err := findRoundWar("#CLAN", tt.round, len(tt.states), getRounds(tt.states, &fetched))
+			switch {
+			case tt.wantRound == 0 && err == nil:
+				t.Errorf("found round %d, want an error", w.TeamSize)
+			case tt.wantRound != 0 && err != nil:
+				t.Errorf("error %v, want round %d", err, tt.wantRound)
+			case tt.wantRound != 0 && w.TeamSize != tt.wantRound:
+				t.Errorf("found round %d, want %d", w.TeamSize, tt.wantRound)
+			}
+			if fetched != tt.wantFetch {
+				t.Errorf("retrieved %d rounds, want %d", fetched, tt.wantFetch)
+			}
+		})
+	}
+
+	fail := errors.New("failed")
+	_, err := findRoundWar("#CLAN", "current", 3, func(i int) (response.ClanWarLeagueWar, bool, error) {
+		return response.ClanWarLeagueWar{}, false, fail
+	})
+	if err != fail {
+		t.Errorf("error %v, want %v", err, fail)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/gsow-swc/coc/pkg/cmd2"
 	"github.com/gsow-swc/coc/pkg/query/request"
 	"github.com/gsow-swc/coc/pkg/query/response"
 	log "github.com/sirupsen/logrus"
@@ -58,11 +59,8 @@ func WarCurrent(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := cmd2.GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -91,11 +89,8 @@ func WarAttack(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := cmd2.GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -177,11 +172,8 @@ func WarDefend(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := cmd2.GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -243,11 +235,8 @@ func WarRoster(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := cmd2.GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -330,11 +319,8 @@ func WarTargets(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := cmd2.GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
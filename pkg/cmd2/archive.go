@@ -0,0 +1,237 @@
+package cmd2
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// archivedWars is a list of wars saved in the archive
+type archivedWars struct {
+	clanName string        // Name of the clan
+	wars     []archivedWar // The saved wars, oldest first
+}
+
+// archivedWar is a summary of a war saved in the archive
+type archivedWar struct {
+	id            string // Identifier of the war
+	cwl           bool   // The war is a CWL war or a regular war
+	state         string // State of the war when it was saved
+	teamSize      int    // Number of members in the war
+	opponentName  string // Name of the clan's opponent
+	result        string // Result of the war, once it has ended
+	stars         int    // Number of stars for the clan
+	opponentStars int    // Number of stars for the opponent
+}
+
+// archiveWars saves the clan's current war and its Clan War League wars in the archive, returning the
+// wars that were retrieved and the number of snapshots that were saved.  A failure to get the current
+// war doesn't stop the league wars being saved, and the errors are returned together at the end.
+func archiveWars(a *archive.Archive, tag string) ([]archive.War, int, error) {
+	var wars []archive.War
+	saved := 0
+
+	// Save the current war, if there is one.  A private war log isn't a failure, as the league wars can
+	// still be retrieved.
+	var warErr error
+	req := request.ClanCurrentWar{Tag: tag}
+	war, err := req.Get()
+	var private *request.PrivateWarLogError
+	switch {
+	case errors.As(err, &private):
+		log.Info(err)
+	case err != nil:
+		log.Error("failed to get the current war for ", tag, ": ", err)
+		warErr = err
+	case war.State != "" && war.State != "notInWar":
+		w := archive.War{ClanTag: tag, War: &war}
+		wars = append(wars, w)
+		ok, err := a.PutWar(w)
+		if err != nil {
+			log.Error("failed to save the war")
+			return wars, saved, err
+		}
+		if ok {
+			saved++
+		}
+	}
+
+	// Save the clan's wars in each round of the Clan War League, skipping rounds whose war was saved
+	// after it ended
+	archived, err := a.Wars(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		return wars, saved, err
+	}
+	ended := make(map[string]bool)
+	for _, w := range archived {
+		if w.IsLeagueWar() && w.State() == "warEnded" {
+			ended[w.WarTag] = true
+		}
+	}
+	leagueWars, leagueErr := getLeagueWars(tag, ended)
+	for _, w := range leagueWars {
+		wars = append(wars, w)
+		ok, err := a.PutWar(w)
+		if err != nil {
+			log.Error("failed to save the war")
+			return wars, saved, err
+		}
+		if ok {
+			saved++
+		}
+	}
+
+	switch {
+	case warErr != nil && leagueErr != nil:
+		return wars, saved, fmt.Errorf("%v; %v", warErr, leagueErr)
+	case leagueErr != nil:
+		return wars, saved, leagueErr
+	}
+	return wars, saved, warErr
+}
+
+// getLeagueWars gets the clan's war in each round of its Clan War League group that has been drawn,
+// leaving out rounds with a war in skip, which is keyed by war tag.  The group doesn't exist when the
+// clan isn't in the league, so that isn't treated as a failure.
+func getLeagueWars(tag string, skip map[string]bool) ([]archive.War, error) {
+	var wars []archive.War
+
+	greq := request.ClanWarLeagueGroup{Tag: tag}
+	group, err := greq.Get()
+	if isNotFound(err) {
+		log.Info("no Clan War League group for ", tag)
+		return wars, nil
+	}
+	if err != nil {
+		log.Error("failed to get the response")
+		return wars, err
+	}
+	for _, round := range group.Rounds {
+		if hasWarTag(round.WarTags, skip) {
+			continue
+		}
+		for _, warTag := range round.WarTags {
+			// Wars in rounds that haven't been drawn have a tag of #0
+			if warTag == "#0" {
+				continue
+			}
+			wreq := request.ClanWarLeagueWar{Tag: warTag}
+			lw, err := wreq.Get()
+			if err != nil {
+				log.Error("failed to get the response")
+				return wars, err
+			}
+			if lw.Clan.Tag != tag && lw.Opponent.Tag != tag {
+				continue
+			}
+			wars = append(wars, archive.War{ClanTag: tag, WarTag: warTag, LeagueWar: &lw})
+			break
+		}
+	}
+	return wars, nil
+}
+
+// hasWarTag returns true if any of the war tags is in the set
+func hasWarTag(warTags []string, set map[string]bool) bool {
+	for _, t := range warTags {
+		if set[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// ArchiveSave saves the current war and Clan War League wars for a clan in the archive
+func ArchiveSave(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	_, n, err := archiveWars(a, tag)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	fmt.Printf("Saved %d war snapshots for %s\n", n, tag)
+
+	return nil
+}
+
+// ArchiveList lists the wars for a clan saved in the archive
+func ArchiveList(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getDateRange(c)
+	if err != nil {
+		return err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	wars, err := a.Wars(tag, from, to)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+
+	list := archivedWars{clanName: tag}
+	for _, w := range wars {
+		cw := w.ClanWar()
+		list.clanName = cw.Clan.Name
+		list.wars = append(list.wars, archivedWar{
+			id:            w.ID(),
+			cwl:           w.IsLeagueWar(),
+			state:         cw.State,
+			teamSize:      cw.TeamSize,
+			opponentName:  cw.Opponent.Name,
+			result:        cw.Result,
+			stars:         cw.Clan.Stars,
+			opponentStars: cw.Opponent.Stars,
+		})
+	}
+	fmt.Println(list)
+
+	return nil
+}
+
+// String returns a string representation of the wars saved in the archive
+func (l archivedWars) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+
+	t.SetTitle(l.clanName + " archived wars")
+	t.AppendHeader(table.Row{"War", "Type", "State", "Size", "Opponent", "Result", "Stars", "OppStars"})
+	for _, w := range l.wars {
+		warType := "war"
+		if w.cwl {
+			warType = "cwl"
+		}
+		t.AppendRow(table.Row{w.id, warType, w.state, w.teamSize, w.opponentName, w.result, w.stars, w.opponentStars})
+	}
+
+	return t.Render()
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gsow-swc/coc/pkg/query/request"
 	"github.com/gsow-swc/coc/pkg/query/response"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 )
@@ -15,18 +16,38 @@ import (
 // clans is an overview of a list of clans
 type clans struct {
 	Clans []clan // List of clans
+	top   int    // Number of strongest members in the strength summary, or 0 if there is none
 }
 
 // clan is an overview of a given clan
 type clan struct {
-	name    string // Name of the clan
-	tag     string // Tag for the clan
-	members int    // Number of members in the clan
-	wins    int    // Number of regular war wins for the clan
-	losses  int    // Number of regular war losses for the clan
-	draws   int    // Number of regular war draws for the clan
-	level   int    // Clan level
-	league  string // CWL league for the clan
+	name    string      // Name of the clan
+	tag     string      // Tag for the clan
+	members int         // Number of members in the clan
+	wins    int         // Number of regular war wins for the clan
+	losses  int         // Number of regular war losses for the clan
+	draws   int         // Number of regular war draws for the clan
+	level   int         // Clan level
+	league  string      // CWL league for the clan
+	top     topStrength // Strength of the strongest members
+}
+
+// clanMembers is the list of members in a clan
+type clanMembers struct {
+	members []clanMember // Members of the clan
+}
+
+// clanMember is a member of a clan, with how close the member's base is to max
+type clanMember struct {
+	name          string              // Name of the member
+	townHall      int                 // Town hall level
+	barbarianKing int                 // Barbarian King level
+	archerQueen   int                 // Archer Queen level
+	grandWarden   int                 // Grand warden level
+	royalChampion int                 // Royal champion level
+	league        string              // League the player is in
+	completion    response.Completion // How close the home village is to max for the town hall
+	strength      float64             // War strength estimate
 }
 
 // ClanList lists all clans that match the provided filters
@@ -43,7 +64,7 @@ func ClanList(c *cli.Context) error {
 	}
 
 	// Make sure at least one filter is specified
-	if req.Name == "" && req.WarFrequency == "" && req.LocationID == 0 && req.MinMembers == 0 && req.MaxMembers == 0 && (req.LabelIDs == nil || len(req.LabelIDs) == 0) && req.MinClanPoints == 0 && req.MaxMembers == 0 {
+	if req.Name == "" && req.WarFrequency == "" && req.LocationID == 0 && req.MinMembers == 0 && req.MaxMembers == 0 && (req.LabelIDs == nil || len(req.LabelIDs) == 0) && req.MinClanPoints == 0 && req.MinClanLevel == 0 {
 		cli.ShowCommandHelpAndExit(c, "ls", -1)
 	}
 
@@ -88,7 +109,7 @@ func ClanGet(c *cli.Context) error {
 		return err
 	}
 
-	clans := clans{}
+	clans := clans{top: c.Int("top")}
 	c2 := clan{
 		name:    c1.Name,
 		tag:     c1.Tag,
@@ -99,6 +120,23 @@ func ClanGet(c *cli.Context) error {
 		level:   c1.ClanLevel,
 		league:  c1.WarLeague.Name,
 	}
+
+	// The strength summary needs the profile of every member
+	if clans.top > 0 {
+		var strengths []float64
+		for _, m := range c1.MemberList {
+			preq := request.Player{Tag: m.Tag}
+			p, err := preq.Get()
+			if err != nil {
+				log.Error("failed to get the response")
+				fmt.Println(err)
+				return err
+			}
+			strengths = append(strengths, p.Strength())
+		}
+		c2.top = getTopStrength(strengths, clans.top)
+	}
+
 	clans.Clans = append(clans.Clans, c2)
 	fmt.Println(clans)
 	return nil
@@ -155,12 +193,11 @@ func ClanMembersGet(c *cli.Context) error {
 		return res
 	})
 
-	clan := warMapClan{}
+	clan := clanMembers{}
 
-	for i, p := range players {
+	for _, p := range players {
 		heroes := getHeroes(p.Heroes)
-		m := warMapClanMember{
-			mapPosition:   i + 1,
+		m := clanMember{
 			name:          p.Name,
 			townHall:      p.TownHallLevel,
 			barbarianKing: heroes.bk,
@@ -168,6 +205,8 @@ func ClanMembersGet(c *cli.Context) error {
 			grandWarden:   heroes.gw,
 			royalChampion: heroes.rc,
 			league:        p.League.Name,
+			completion:    p.Completion(),
+			strength:      p.Strength(),
 		}
 
 		clan.members = append(clan.members, m)
@@ -182,9 +221,17 @@ func ClanMembersGet(c *cli.Context) error {
 func (cs clans) String() string {
 	t := table.NewWriter()
 	t.SetStyle(table.StyleColoredBright)
-	t.AppendHeader(table.Row{"Name", "Tag", "Members", "Wins", "Losses", "Draws", "Level", "League"})
+	if cs.top > 0 {
+		t.AppendHeader(table.Row{"Name", "Tag", "Members", "Wins", "Losses", "Draws", "Level", "League", fmt.Sprintf("Top %d Strength", cs.top)})
+	} else {
+		t.AppendHeader(table.Row{"Name", "Tag", "Members", "Wins", "Losses", "Draws", "Level", "League"})
+	}
 	for _, c := range cs.Clans {
-		t.AppendRow(table.Row{c.name, c.tag, c.members, c.wins, c.losses, c.draws, c.level, c.league})
+		if cs.top > 0 {
+			t.AppendRow(table.Row{c.name, c.tag, c.members, c.wins, c.losses, c.draws, c.level, c.league, c.top})
+		} else {
+			t.AppendRow(table.Row{c.name, c.tag, c.members, c.wins, c.losses, c.draws, c.level, c.league})
+		}
 	}
 	return t.Render()
 }
@@ -199,3 +246,36 @@ func (c clan) String() string {
 
 	return t.Render()
 }
+
+// String returns a string representation of the members of a clan.
+func (cm clanMembers) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 8, Align: text.AlignRight},
+		{Number: 9, Align: text.AlignRight},
+		{Number: 10, Align: text.AlignRight},
+		{Number: 11, Align: text.AlignRight},
+	})
+
+	t.AppendHeader(table.Row{"#", "Name", "TH", "BK", "AQ", "GW", "RC", "Rushed", "Offense", "Heroes", "Strength", "League"})
+	for i, m := range cm.members {
+		league := m.league
+		if league == "" {
+			league = "Unranked"
+		}
+		t.AppendRow(table.Row{
+			i + 1, m.name, m.townHall, m.barbarianKing, m.archerQueen, m.grandWarden, m.royalChampion,
+			getPercent(m.completion.Rushed, m.completion.Known),
+			getPercent(m.completion.Offense, m.completion.Known),
+			getPercent(m.completion.Heroes, m.completion.Known),
+			fmt.Sprintf("%.0f", m.strength),
+			league,
+		})
+		if !m.completion.Known {
+			t.SetCaption("%s", getCompletionNote())
+		}
+	}
+
+	return t.Render()
+}
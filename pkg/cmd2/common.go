@@ -1,11 +1,16 @@
 package cmd2
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/http"
 	"github.com/gsow-swc/coc/pkg/query/request"
 	"github.com/gsow-swc/coc/pkg/query/response"
 	log "github.com/sirupsen/logrus"
@@ -47,6 +52,46 @@ func getHeroes(hs []response.Troop) heroes {
 	return heroes
 }
 
+// total returns the sum of the hero levels
+func (h heroes) total() int {
+	return h.bk + h.aq + h.gw + h.rc
+}
+
+// topStrength is the combined war strength of the strongest players in a clan
+type topStrength struct {
+	count int     // Number of players included
+	total float64 // Sum of the strength of the players
+}
+
+// getTopStrength returns the combined strength of the n strongest of the given strengths, or of all of
+// them if n is zero or there are fewer than n
+func getTopStrength(strengths []float64, n int) topStrength {
+	sorted := append([]float64{}, strengths...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	if n <= 0 || n > len(sorted) {
+		n = len(sorted)
+	}
+
+	s := topStrength{count: n}
+	for _, v := range sorted[:n] {
+		s.total += v
+	}
+	return s
+}
+
+// average returns the average strength of the players included
+func (s topStrength) average() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.total / float64(s.count)
+}
+
+// String returns a string representation of the strength of the strongest players
+func (s topStrength) String() string {
+	return fmt.Sprintf("%.0f (avg %.0f)", s.total, s.average())
+}
+
 // getClan finds the clan with the given name.  If no clan is found or of more than one clan
 // is found, an error is returned.
 func getClan(name string) (response.Clan, error) {
@@ -82,7 +127,7 @@ func getClan(name string) (response.Clan, error) {
 // getTag gets the clan tag from the `clan` option or, if that is not present, from the `name` option
 func getTag(c *cli.Context) (string, error) {
 	// Get the tag of the clan
-	tag := c.String("clan")
+	tag := response.NormalizeTag(c.String("clan"))
 	if tag == "" {
 		name := c.String("name")
 		if name == "" {
@@ -100,8 +145,7 @@ func getTag(c *cli.Context) (string, error) {
 
 // getTime parses the time returned as a string into a time object
 func getTime(t string) time.Time {
-	layout := "20060102T150405.000Z"
-	time, _ := time.Parse(layout, t)
+	time, _ := response.ParseTime(t)
 	return time
 }
 
@@ -149,6 +193,31 @@ func getTimeLeft(t time.Time) string {
 	return b.String()
 }
 
+// getDuration returns a string representation of a duration in days, hours and minutes
+func getDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+
+	var parts []string
+	for _, p := range []struct {
+		n    int
+		unit string
+	}{{days, "day"}, {hours, "hour"}, {minutes, "minute"}} {
+		if p.n == 1 {
+			parts = append(parts, "1 "+p.unit)
+		} else if p.n > 1 {
+			parts = append(parts, strconv.Itoa(p.n)+" "+p.unit+"s")
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
 // getStars returns a string representation of the number of stars that have
 // been gained via an attack against a given base.
 func getStars(stars int) string {
@@ -161,3 +230,138 @@ func getStars(stars int) string {
 	}
 	return threestar
 }
+
+// OpenArchive opens the local archive given by the `archive` option or, if that is not present, the
+// archive in the configuration or in the default location
+func OpenArchive(c *cli.Context) (*archive.Archive, error) {
+	path := c.String("archive")
+	if path == "" {
+		path = config.Data.Archive
+	}
+	if path == "" {
+		var err error
+		path, err = archive.DefaultPath()
+		if err != nil {
+			log.Error("failed to find the default archive")
+			fmt.Println(err)
+			return nil, err
+		}
+	}
+
+	a, err := archive.Open(path)
+	if err != nil {
+		log.Error("failed to open the archive ", path)
+		fmt.Println(err)
+		return nil, err
+	}
+	return a, nil
+}
+
+// getDateRange gets the range of dates from the `from` and `to` options.  The `to` date is inclusive,
+// so the returned end of the range is the start of the following day.
+func getDateRange(c *cli.Context) (time.Time, time.Time, error) {
+	const layout = "2006-01-02"
+	var from, to time.Time
+	var err error
+
+	if s := c.String("from"); s != "" {
+		from, err = time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			err = fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", s)
+			fmt.Println(err)
+			return from, to, err
+		}
+	}
+	if s := c.String("to"); s != "" {
+		to, err = time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			err = fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", s)
+			fmt.Println(err)
+			return from, to, err
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+// isNotFound returns true if the error is the server reporting that what was requested doesn't exist
+func isNotFound(err error) bool {
+	var se *http.StatusError
+	return errors.As(err, &se) && se.StatusCode == 404
+}
+
+// GetLeagueGroup gets the clan's Clan War League group.  The server reports that the group doesn't exist
+// when the clan isn't in the league, and any other error is returned unchanged.
+func GetLeagueGroup(tag string) (response.ClanWarLeagueGroup, error) {
+	req := request.ClanWarLeagueGroup{Tag: tag}
+	group, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		if isNotFound(err) {
+			err = fmt.Errorf("clan %s is not in a Clan War League group", tag)
+		}
+		fmt.Println(err)
+		return group, err
+	}
+	return group, nil
+}
+
+// GetCurrentWar gets the current war for the clan or, if the `war` option is present, the war with
+// that identifier from the archive
+func GetCurrentWar(c *cli.Context, tag string) (response.ClanWar, error) {
+	if c.String("war") != "" {
+		w, err := GetArchivedWar(c, tag)
+		if err != nil {
+			return response.ClanWar{}, err
+		}
+		return w.ClanWar(), nil
+	}
+
+	req := request.ClanCurrentWar{Tag: tag}
+	war, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return war, err
+	}
+	return war, nil
+}
+
+// getCurrentWarAttacks gets the war like GetCurrentWar, along with the number of attacks each member has
+// in it.  The current war from the API is always a regular war.
+func getCurrentWarAttacks(c *cli.Context, tag string) (response.ClanWar, int, error) {
+	if c.String("war") != "" {
+		w, err := GetArchivedWar(c, tag)
+		if err != nil {
+			return response.ClanWar{}, 0, err
+		}
+		return w.ClanWar(), w.AttacksPerMember(), nil
+	}
+	war, err := GetCurrentWar(c, tag)
+	return war, warAttacksPerMember, err
+}
+
+// GetArchivedWar gets the war for the clan with the identifier in the `war` option from the archive
+func GetArchivedWar(c *cli.Context, tag string) (archive.War, error) {
+	id := c.String("war")
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return archive.War{}, err
+	}
+	defer a.Close()
+
+	w, ok, err := a.War(tag, id)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return w, err
+	}
+	if !ok {
+		err := fmt.Errorf("war %s for clan %s is not in the archive", id, tag)
+		fmt.Println(err)
+		return w, err
+	}
+	return w, nil
+}
@@ -0,0 +1,281 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// cwlWinBonus is the number of stars added to a clan's total for each CWL war it wins
+	cwlWinBonus = 10
+)
+
+// cwlGroup is a clan's Clan War League group, with the wars in each round that has been drawn
+type cwlGroup struct {
+	group  response.ClanWarLeagueGroup   // The group
+	rounds [][]response.ClanWarLeagueWar // Wars in each round, empty for rounds that haven't been drawn yet
+}
+
+// cwlStanding is a clan's position in its Clan War League group
+type cwlStanding struct {
+	name        string  // Name of the clan
+	tag         string  // Tag of the clan
+	wins        int     // Number of wars won
+	losses      int     // Number of wars lost
+	ties        int     // Number of wars tied
+	stars       int     // Stars earned, including the bonus for each war won
+	destruction float64 // Total destruction, the sum of the destruction percentage of each war times its size
+	projection  string  // Projected promotion or demotion at the end of the season
+}
+
+// cwlRound is the result of one round of the Clan War League for a clan
+type cwlRound struct {
+	round               int     // Number of the round
+	state               string  // State of the war, or empty if the round hasn't been drawn
+	opponentName        string  // Name of the opponent
+	stars               int     // Stars for the clan
+	opponentStars       int     // Stars for the opponent
+	destruction         float32 // Destruction percentage for the clan
+	opponentDestruction float32 // Destruction percentage for the opponent
+	result              string  // Result of the war, once it has ended
+}
+
+// cwlSeason is the standings of a Clan War League group and the results of a clan in each round
+type cwlSeason struct {
+	clanTag   string        // Tag of the clan
+	season    string        // Season of the league
+	league    string        // War league of the clan
+	state     string        // State of the group
+	standings []cwlStanding // Standings of the clans in the group, first place first
+	rounds    []cwlRound    // Results for the clan in each round
+}
+
+// getCwlGroup gets the clan's Clan War League group and all the wars in the rounds that have been drawn
+func getCwlGroup(tag string) (cwlGroup, error) {
+	var g cwlGroup
+
+	group, err := GetLeagueGroup(tag)
+	if err != nil {
+		return g, err
+	}
+	g.group = group
+
+	for _, round := range group.Rounds {
+		var wars []response.ClanWarLeagueWar
+		for _, warTag := range round.WarTags {
+			// Wars in rounds that haven't been drawn have a tag of #0
+			if warTag == "#0" {
+				continue
+			}
+			wreq := request.ClanWarLeagueWar{Tag: warTag}
+			war, err := wreq.Get()
+			if err != nil {
+				log.Error("failed to get the response")
+				fmt.Println(err)
+				return g, err
+			}
+			wars = append(wars, war)
+		}
+		g.rounds = append(g.rounds, wars)
+	}
+
+	return g, nil
+}
+
+// clanWar returns the clan's war in a round, oriented so the clan is the `Clan` of the war, and false
+// if the clan has no war in the round
+func clanWar(wars []response.ClanWarLeagueWar, tag string) (response.ClanWarLeagueWar, bool) {
+	for _, w := range wars {
+		if w.Clan.Tag == tag {
+			return w, true
+		}
+		if w.Opponent.Tag == tag {
+			w.Clan, w.Opponent = w.Opponent, w.Clan
+			return w, true
+		}
+	}
+	return response.ClanWarLeagueWar{}, false
+}
+
+// cwlWarResult returns the result of a CWL war for the `Clan` of the war.  The clan with more stars wins,
+// and a tie in stars goes to the clan with the higher destruction.  An empty result is returned until
+// the war has ended.
+func cwlWarResult(w response.ClanWarLeagueWar) string {
+	if w.State != "warEnded" {
+		return ""
+	}
+	switch {
+	case w.Clan.Stars > w.Opponent.Stars:
+		return "win"
+	case w.Clan.Stars < w.Opponent.Stars:
+		return "lose"
+	case w.Clan.DestructionPercentage > w.Opponent.DestructionPercentage:
+		return "win"
+	case w.Clan.DestructionPercentage < w.Opponent.DestructionPercentage:
+		return "lose"
+	}
+	return "tie"
+}
+
+// getCwlStandings returns the standings of the clans in the group, using the in-game rules: clans are
+// ranked by stars, including the bonus for each war won, and then by total destruction.  Wars in
+// preparation aren't counted, and wars in progress count their stars and destruction so far.
+func getCwlStandings(g cwlGroup) []cwlStanding {
+	standings := make(map[string]*cwlStanding)
+	for _, c := range g.group.Clans {
+		standings[c.Tag] = &cwlStanding{name: c.Name, tag: c.Tag}
+	}
+
+	for _, wars := range g.rounds {
+		for _, w := range wars {
+			if w.State == "preparation" || w.State == "" {
+				continue
+			}
+			for _, side := range []response.ClanWarLeagueWar{w, {Clan: w.Opponent, Opponent: w.Clan, State: w.State}} {
+				s, ok := standings[side.Clan.Tag]
+				if !ok {
+					s = &cwlStanding{name: side.Clan.Name, tag: side.Clan.Tag}
+					standings[side.Clan.Tag] = s
+				}
+				s.stars += side.Clan.Stars
+				s.destruction += float64(side.Clan.DestructionPercentage) * float64(w.TeamSize)
+				switch cwlWarResult(side) {
+				case "win":
+					s.wins++
+					s.stars += cwlWinBonus
+				case "lose":
+					s.losses++
+				case "tie":
+					s.ties++
+				}
+			}
+		}
+	}
+
+	var list []cwlStanding
+	for _, s := range standings {
+		list = append(list, *s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].stars != list[j].stars {
+			return list[i].stars > list[j].stars
+		}
+		if list[i].destruction != list[j].destruction {
+			return list[i].destruction > list[j].destruction
+		}
+		return strings.ToLower(list[i].name) < strings.ToLower(list[j].name)
+	})
+	return list
+}
+
+// CwlSeason gets the standings of the clan's Clan War League group over all rounds, the projected
+// promotions and demotions, and the clan's result in each round
+func CwlSeason(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	g, err := getCwlGroup(tag)
+	if err != nil {
+		return err
+	}
+
+	// The promotions and demotions depend on the clan's war league
+	creq := request.Clan{Tag: tag}
+	clan, err := creq.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	s := cwlSeason{
+		clanTag:   tag,
+		season:    g.group.Season,
+		league:    clan.WarLeague.Name,
+		state:     g.group.State,
+		standings: getCwlStandings(g),
+	}
+	if promoted, demoted, ok := response.WarLeagueMovement(clan.WarLeague.Name); ok {
+		for i := range s.standings {
+			switch {
+			case i < promoted:
+				s.standings[i].projection = "promotion"
+			case i >= len(s.standings)-demoted:
+				s.standings[i].projection = "demotion"
+			}
+		}
+	} else {
+		log.Debug("unknown war league ", clan.WarLeague.Name)
+	}
+
+	for i, wars := range g.rounds {
+		r := cwlRound{round: i + 1}
+		if w, ok := clanWar(wars, tag); ok {
+			r.state = w.State
+			r.opponentName = w.Opponent.Name
+			r.stars = w.Clan.Stars
+			r.opponentStars = w.Opponent.Stars
+			r.destruction = w.Clan.DestructionPercentage
+			r.opponentDestruction = w.Opponent.DestructionPercentage
+			r.result = cwlWarResult(w)
+		}
+		s.rounds = append(s.rounds, r)
+	}
+
+	fmt.Println(s)
+
+	return nil
+}
+
+// String returns a string representation of a Clan War League season
+func (s cwlSeason) String() string {
+	st := table.NewWriter()
+	st.SetStyle(table.StyleColoredBright)
+	st.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, Align: text.AlignRight},
+	})
+
+	league := s.league
+	if league == "" {
+		league = "Unranked"
+	}
+	st.SetTitle(fmt.Sprintf("CWL %s, %s (%s)", s.season, league, s.state))
+	st.AppendHeader(table.Row{"#", "Clan", "W-L-T", "Stars", "Destruction", "Projection"})
+	for i, c := range s.standings {
+		name := c.name
+		if c.tag == s.clanTag {
+			name = "\033[1m" + name + "\033[0m"
+		}
+		record := strconv.Itoa(c.wins) + "-" + strconv.Itoa(c.losses) + "-" + strconv.Itoa(c.ties)
+		st.AppendRow(table.Row{i + 1, name, record, c.stars, fmt.Sprintf("%.0f%%", c.destruction), c.projection})
+	}
+
+	rt := table.NewWriter()
+	rt.SetStyle(table.StyleColoredBright)
+	rt.SetTitle("Rounds")
+	rt.AppendHeader(table.Row{"Round", "Opponent", "State", "Stars", "Destruction", "Result"})
+	for _, r := range s.rounds {
+		if r.state == "" {
+			rt.AppendRow(table.Row{r.round, "", "not drawn"})
+			continue
+		}
+		stars := strconv.Itoa(r.stars) + " - " + strconv.Itoa(r.opponentStars)
+		destruction := fmt.Sprintf("%.1f%% - %.1f%%", r.destruction, r.opponentDestruction)
+		rt.AppendRow(table.Row{r.round, r.opponentName, r.state, stars, destruction, r.result})
+	}
+
+	return st.Render() + "\n" + rt.Render()
+}
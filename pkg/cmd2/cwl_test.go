@@ -0,0 +1,97 @@
+package cmd2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testLeagueWar returns a CWL war between two clans with the given stars and destruction
+func testLeagueWar(state string, clan string, stars int, destruction float32, opponent string, opponentStars int, opponentDestruction float32) response.ClanWarLeagueWar {
+	return response.ClanWarLeagueWar{
+		State:    state,
+		TeamSize: 15,
+		Clan:     response.ClanWarTeam{Tag: clan, Name: clan, Stars: stars, DestructionPercentage: destruction},
+		Opponent: response.ClanWarTeam{Tag: opponent, Name: opponent, Stars: opponentStars, DestructionPercentage: opponentDestruction},
+	}
+}
+
+func TestCwlWarResult(t *testing.T) {
+	tests := []struct {
+		name string
+		war  response.ClanWarLeagueWar
+		want string
+	}{
+		{"more stars", testLeagueWar("warEnded", "#A", 30, 80, "#B", 25, 90), "win"},
+		{"fewer stars", testLeagueWar("warEnded", "#A", 20, 95, "#B", 25, 70), "lose"},
+		{"same stars, more destruction", testLeagueWar("warEnded", "#A", 25, 81.5, "#B", 25, 81.2), "win"},
+		{"same stars, less destruction", testLeagueWar("warEnded", "#A", 25, 70, "#B", 25, 81), "lose"},
+		{"tie", testLeagueWar("warEnded", "#A", 25, 80, "#B", 25, 80), "tie"},
+		{"in progress", testLeagueWar("inWar", "#A", 30, 80, "#B", 25, 90), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cwlWarResult(tt.war); got != tt.want {
+				t.Errorf("cwlWarResult = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCwlStandings(t *testing.T) {
+	var g cwlGroup
+	if err := json.Unmarshal([]byte(`{"clans": [
+		{"tag": "#A", "name": "#A"}, {"tag": "#B", "name": "#B"}, {"tag": "#C", "name": "#C"}, {"tag": "#D", "name": "#D"}
+	]}`), &g.group); err != nil {
+		t.Fatal(err)
+	}
+	g.rounds = [][]response.ClanWarLeagueWar{
+		{
+			testLeagueWar("warEnded", "#A", 30, 90, "#B", 20, 70),
+			testLeagueWar("warEnded", "#D", 25, 80, "#C", 25, 85),
+		},
+		{
+			// Clans are listed in either order
+			testLeagueWar("warEnded", "#C", 28, 88, "#A", 28, 88),
+			testLeagueWar("inWar", "#B", 10, 30, "#D", 5, 20),
+		},
+		{
+			testLeagueWar("preparation", "#A", 0, 0, "#D", 0, 0),
+			testLeagueWar("preparation", "#B", 0, 0, "#C", 0, 0),
+		},
+		// A round that hasn't been drawn
+		nil,
+	}
+
+	tests := []struct {
+		tag         string
+		stars       int
+		destruction float64
+		wins        int
+		losses      int
+		ties        int
+	}{
+		{"#A", 30 + cwlWinBonus + 28, (90 + 88) * 15, 1, 0, 1},
+		{"#C", 25 + cwlWinBonus + 28, (85 + 88) * 15, 1, 0, 1},
+		{"#B", 20 + 10, (70 + 30) * 15, 0, 1, 0},
+		{"#D", 25 + 5, (80 + 20) * 15, 0, 1, 0},
+	}
+	standings := getCwlStandings(g)
+	if len(standings) != len(tests) {
+		t.Fatalf("got %d standings, want %d", len(standings), len(tests))
+	}
+	for i, tt := range tests {
+		s := standings[i]
+		if s.tag != tt.tag {
+			t.Errorf("place %d is %s, want %s", i+1, s.tag, tt.tag)
+			continue
+		}
+		if s.stars != tt.stars || s.destruction != tt.destruction {
+			t.Errorf("%s has %d stars and %.0f destruction, want %d and %.0f", s.tag, s.stars, s.destruction, tt.stars, tt.destruction)
+		}
+		if s.wins != tt.wins || s.losses != tt.losses || s.ties != tt.ties {
+			t.Errorf("%s is %d-%d-%d, want %d-%d-%d", s.tag, s.wins, s.losses, s.ties, tt.wins, tt.losses, tt.ties)
+		}
+	}
+}
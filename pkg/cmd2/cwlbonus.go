@@ -0,0 +1,249 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// bonusWeights are the weights given to each part of a member's CWL bonus score
+type bonusWeights struct {
+	stars       float64 // Weight for each star earned
+	destruction float64 // Weight for each 100% of destruction
+	attacks     float64 // Weight for each attack used
+	townHall    float64 // Weight for each town hall level hit above the attacker's, negative for hitting down
+	defense     float64 // Weight for each defense that wasn't three starred
+}
+
+// defaultBonusWeights are the weights used when none are given in the options or the configuration
+var defaultBonusWeights = bonusWeights{stars: 2, destruction: 1, attacks: 1, townHall: 1, defense: 1}
+
+// bonusMember is a member's performance over a CWL season
+type bonusMember struct {
+	name        string  // Name of the member
+	tag         string  // Tag of the member
+	townHall    int     // Town hall level in the most recent round
+	rounds      int     // Number of rounds the member was in
+	available   int     // Number of attacks available to the member
+	attacks     int     // Number of attacks used
+	stars       int     // Stars earned
+	destruction int     // Total destruction percentage of the attacks
+	townHallGap int     // Sum of the defender's town hall level less the attacker's for each attack
+	defenses    int     // Number of times the member's base was attacked in an ended war
+	holds       int     // Number of those defenses that weren't three starred
+	score       float64 // Weighted score
+	bonus       bool    // The member is proposed for a bonus medal reward
+}
+
+// cwlBonus is the proposed bonus medal recipients for a CWL season
+type cwlBonus struct {
+	clanName string        // Name of the clan
+	season   string        // Season of the league
+	bonuses  int           // Number of bonus medal rewards
+	csv      bool          // Output as CSV instead of a table
+	members  []bonusMember // Members ranked by score
+}
+
+// getWeight gets a weight from an option or, if that is not present, from the configuration or the
+// default.  A weight of zero turns that part of the score off.
+func getWeight(c *cli.Context, name string, configured *float64, def float64) float64 {
+	if c.IsSet(name) {
+		return c.Float64(name)
+	}
+	if configured != nil {
+		return *configured
+	}
+	return def
+}
+
+// getBonusWeights gets the weights for the CWL bonus score
+func getBonusWeights(c *cli.Context) bonusWeights {
+	cfg := config.Data.CwlBonus
+	return bonusWeights{
+		stars:       getWeight(c, "stars-weight", cfg.Stars, defaultBonusWeights.stars),
+		destruction: getWeight(c, "destruction-weight", cfg.Destruction, defaultBonusWeights.destruction),
+		attacks:     getWeight(c, "attacks-weight", cfg.Attacks, defaultBonusWeights.attacks),
+		townHall:    getWeight(c, "th-weight", cfg.TownHall, defaultBonusWeights.townHall),
+		defense:     getWeight(c, "defense-weight", cfg.Defense, defaultBonusWeights.defense),
+	}
+}
+
+// getBonusMembers totals the attacks and defenses of the clan's members over the rounds of a CWL season,
+// keyed by the tag of the member.  Wars in preparation are ignored, and defenses are only counted once a
+// war has ended.
+func getBonusMembers(g cwlGroup, tag string) map[string]*bonusMember {
+	members := make(map[string]*bonusMember)
+	for _, wars := range g.rounds {
+		w, ok := clanWar(wars, tag)
+		if !ok || w.State == "preparation" {
+			continue
+		}
+
+		defenderTH := make(map[string]int)
+		for _, m := range w.Opponent.Members {
+			defenderTH[m.Tag] = m.TownhallLevel
+		}
+
+		for _, m := range w.Clan.Members {
+			bm, ok := members[m.Tag]
+			if !ok {
+				bm = &bonusMember{tag: m.Tag}
+				members[m.Tag] = bm
+			}
+			bm.name = m.Name
+			bm.townHall = m.TownhallLevel
+			bm.rounds++
+
+			// Attacks can still be made until the war ends
+			if w.State == "warEnded" {
+				bm.available++
+			} else {
+				bm.available += len(m.Attacks)
+			}
+			for _, a := range m.Attacks {
+				bm.attacks++
+				bm.stars += a.Stars
+				bm.destruction += a.DestructionPercentage
+				bm.townHallGap += defenderTH[a.DefenderTag] - m.TownhallLevel
+			}
+
+			if w.State == "warEnded" && m.OpponentAttacks > 0 {
+				bm.defenses++
+				if m.BestOpponentAttack.Stars < 3 {
+					bm.holds++
+				}
+			}
+		}
+	}
+	return members
+}
+
+// getScore returns the member's weighted score
+func (m bonusMember) getScore(w bonusWeights) float64 {
+	return w.stars*float64(m.stars) +
+		w.destruction*float64(m.destruction)/100 +
+		w.attacks*float64(m.attacks) +
+		w.townHall*float64(m.townHallGap) +
+		w.defense*float64(m.holds)
+}
+
+// CwlBonus scores each member over the rounds of the CWL season and proposes the recipients of the
+// bonus medal rewards
+func CwlBonus(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	format := strings.ToLower(c.String("format"))
+	if format == "" {
+		format = "table"
+	}
+	if format != "table" && format != "csv" {
+		err := fmt.Errorf("invalid format %q, expected table or csv", format)
+		fmt.Println(err)
+		return err
+	}
+
+	g, err := getCwlGroup(tag)
+	if err != nil {
+		return err
+	}
+
+	b := cwlBonus{season: g.group.Season, csv: format == "csv"}
+	weights := getBonusWeights(c)
+	for _, m := range getBonusMembers(g, tag) {
+		m.score = m.getScore(weights)
+		b.members = append(b.members, *m)
+	}
+	sort.Slice(b.members, func(i, j int) bool {
+		if b.members[i].score != b.members[j].score {
+			return b.members[i].score > b.members[j].score
+		}
+		if b.members[i].stars != b.members[j].stars {
+			return b.members[i].stars > b.members[j].stars
+		}
+		return strings.ToLower(b.members[i].name) < strings.ToLower(b.members[j].name)
+	})
+
+	// The number of bonuses depends on the clan's war league and the wars it has won
+	wins := 0
+	for _, wars := range g.rounds {
+		if w, ok := clanWar(wars, tag); ok {
+			b.clanName = w.Clan.Name
+			if cwlWarResult(w) == "win" {
+				wins++
+			}
+		}
+	}
+	if c.IsSet("count") {
+		b.bonuses = c.Int("count")
+	} else {
+		creq := request.Clan{Tag: tag}
+		clan, err := creq.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		var ok bool
+		b.bonuses, ok = response.WarLeagueBonuses(clan.WarLeague.Name, wins)
+		if !ok {
+			err := fmt.Errorf("unknown war league %q, use the --count option", clan.WarLeague.Name)
+			fmt.Println(err)
+			return err
+		}
+	}
+	for i := range b.members {
+		b.members[i].bonus = i < b.bonuses
+	}
+
+	fmt.Println(b)
+
+	return nil
+}
+
+// String returns a string representation of the proposed bonus medal recipients
+func (b cwlBonus) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 7, Align: text.AlignRight},
+		{Number: 8, Align: text.AlignRight},
+		{Number: 10, Align: text.AlignRight},
+	})
+
+	// A title would not be a valid CSV row
+	if !b.csv {
+		t.SetTitle(fmt.Sprintf("%s CWL %s bonus medals (%d rewards)", b.clanName, b.season, b.bonuses))
+	}
+	t.AppendHeader(table.Row{"#", "Name", "Tag", "TH", "Attacks", "Stars", "Destruction", "TH Gap", "Holds", "Score", "Bonus"})
+	for i, m := range b.members {
+		bonus := ""
+		if m.bonus {
+			bonus = "yes"
+		}
+		t.AppendRow(table.Row{
+			i + 1, m.name, m.tag, m.townHall,
+			strconv.Itoa(m.attacks) + "/" + strconv.Itoa(m.available),
+			m.stars, m.destruction, fmt.Sprintf("%+d", m.townHallGap),
+			strconv.Itoa(m.holds) + "/" + strconv.Itoa(m.defenses),
+			fmt.Sprintf("%.1f", m.score), bonus,
+		})
+	}
+
+	if b.csv {
+		return t.RenderCSV()
+	}
+	return t.Render()
+}
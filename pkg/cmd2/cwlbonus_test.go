@@ -0,0 +1,100 @@
+package cmd2
+
+import (
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+func TestGetBonusMembers(t *testing.T) {
+	// testBonusWar returns a war in which the clan's member #A attacks once and has its base three
+	// starred, #B holds its base and #C does nothing
+	testBonusWar := func(state string, swap bool) response.ClanWarLeagueWar {
+		w := testLeagueWar(state, "#CLAN", 0, 0, "#OPP", 0, 0)
+		w.Clan.Members = []response.ClanWarMember{
+			{Tag: "#A", Name: "a", TownhallLevel: 14, Attacks: []response.ClanWarAttack{testAttack("#X", 3, 100)},
+				OpponentAttacks: 1, BestOpponentAttack: testAttack("#A", 3, 100)},
+			{Tag: "#B", Name: "b", TownhallLevel: 13, OpponentAttacks: 2, BestOpponentAttack: testAttack("#B", 2, 90)},
+			{Tag: "#C", Name: "c", TownhallLevel: 12},
+		}
+		w.Opponent.Members = []response.ClanWarMember{
+			{Tag: "#X", TownhallLevel: 15},
+			{Tag: "#Y", TownhallLevel: 12},
+		}
+		if swap {
+			w.Clan, w.Opponent = w.Opponent, w.Clan
+		}
+		return w
+	}
+	b := testBonusWar("inWar", false)
+	b.Clan.Members[1].Attacks = []response.ClanWarAttack{testAttack("#Y", 2, 80)}
+
+	g := cwlGroup{rounds: [][]response.ClanWarLeagueWar{
+		{testBonusWar("warEnded", false)},
+		// The clan may be listed as the opponent
+		{testBonusWar("warEnded", true)},
+		// Attacks still count in a war in progress, but defenses don't
+		{b},
+		{testBonusWar("preparation", false)},
+		// Rounds that haven't been drawn yet
+		{},
+	}}
+
+	members := getBonusMembers(g, "#CLAN")
+	tests := []struct {
+		tag         string
+		rounds      int
+		available   int
+		attacks     int
+		stars       int
+		destruction int
+		townHallGap int
+		defenses    int
+		holds       int
+	}{
+		{"#A", 3, 3, 3, 9, 300, 3, 2, 0},
+		{"#B", 3, 3, 1, 2, 80, -1, 2, 2},
+		{"#C", 3, 2, 0, 0, 0, 0, 0, 0},
+	}
+	if len(members) != len(tests) {
+		t.Fatalf("got %d members, want %d", len(members), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			m := members[tt.tag]
+			if m == nil {
+				t.Fatalf("no member %s", tt.tag)
+			}
+			got := []int{m.rounds, m.available, m.attacks, m.stars, m.destruction, m.townHallGap, m.defenses, m.holds}
+			want := []int{tt.rounds, tt.available, tt.attacks, tt.stars, tt.destruction, tt.townHallGap, tt.defenses, tt.holds}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("rounds, available, attacks, stars, destruction, gap, defenses, holds = %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestBonusMemberGetScore(t *testing.T) {
+	m := bonusMember{stars: 8, destruction: 350, attacks: 4, townHallGap: -2, holds: 3}
+	tests := []struct {
+		name    string
+		weights bonusWeights
+		want    float64
+	}{
+		{"default weights", defaultBonusWeights, 16 + 3.5 + 4 - 2 + 3},
+		{"stars only", bonusWeights{stars: 1}, 8},
+		{"destruction only", bonusWeights{destruction: 2}, 7},
+		{"town hall gap only", bonusWeights{townHall: 0.5}, -1},
+		{"no weights", bonusWeights{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.getScore(tt.weights); got != tt.want {
+				t.Errorf("getScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultCwlTeamSize is the lineup size used when it can't be found from the wars in the group
+	defaultCwlTeamSize = 15
+
+	// Reasons a member is or isn't in the planned lineup
+	reasonAlwaysIn = "always in"
+	reasonMinWars  = "needs wars"
+	reasonStrength = "strength"
+	reasonBenched  = "benched"
+	reasonMissed   = "missed attacks"
+	reasonFull     = "lineup full"
+)
+
+// planMember is a member of the clan's CWL roster considered for a lineup
+type planMember struct {
+	name     string  // Name of the member
+	tag      string  // Tag of the member
+	townHall int     // Town hall level
+	heroes   heroes  // Hero levels
+	strength float64 // War strength estimate
+	played   int     // Number of earlier rounds the member was in
+	attacks  int     // Number of attacks used in earlier rounds
+	missed   int     // Number of attacks missed in earlier rounds that have ended
+	alwaysIn bool    // The member is always put in the lineup
+	benched  bool    // The member is never put in the lineup
+	mustPlay bool    // The member needs every remaining round to reach the minimum number of wars
+	selected bool    // The member is in the planned lineup
+	reason   string  // Why the member is or isn't in the lineup
+}
+
+// cwlPlan is a recommended lineup for a round of the Clan War League
+type cwlPlan struct {
+	clanName string       // Name of the clan
+	round    int          // Number of the round
+	rounds   int          // Number of rounds in the season
+	teamSize int          // Number of members in the lineup
+	minWars  int          // Minimum number of wars for each member
+	members  []planMember // Members in the lineup, strongest first, followed by those left out
+}
+
+// matchesPlayer returns true if a player with the given tag and name is one of the players in the list,
+// each of which may be a tag or a name
+func matchesPlayer(tag string, name string, players []string) bool {
+	for _, p := range players {
+		if strings.EqualFold(p, tag) || strings.EqualFold("#"+p, tag) || strings.EqualFold(p, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// getPlanRound gets the round to plan from the `round` option or, if that is not present, the first round
+// for which the clan's war hasn't started
+func getPlanRound(c *cli.Context, g cwlGroup, tag string) (int, error) {
+	if r := c.Int("round"); r != 0 {
+		if r < 1 || r > len(g.group.Rounds) {
+			err := fmt.Errorf("invalid round %d, the group has %d rounds", r, len(g.group.Rounds))
+			fmt.Println(err)
+			return 0, err
+		}
+		return r, nil
+	}
+
+	for i, wars := range g.rounds {
+		if w, ok := clanWar(wars, tag); !ok || w.State == "preparation" {
+			return i + 1, nil
+		}
+	}
+	err := fmt.Errorf("all rounds of the Clan War League have started, use the --round option")
+	fmt.Println(err)
+	return 0, err
+}
+
+// CwlPlan recommends a lineup for a round of the Clan War League from the clan's roster, using the
+// strength of each member, their attacks in earlier rounds and the lineup rules
+func CwlPlan(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	g, err := getCwlGroup(tag)
+	if err != nil {
+		return err
+	}
+	round, err := getPlanRound(c, g, tag)
+	if err != nil {
+		return err
+	}
+
+	// Lineup rules from the options, falling back to the configuration
+	rules := config.Data.CwlPlan
+	minWars := rules.MinWars
+	if c.IsSet("min-wars") {
+		minWars = c.Int("min-wars")
+	}
+	alwaysIn := append(append([]string{}, rules.AlwaysIn...), c.StringSlice("always-in")...)
+	bench := append(append([]string{}, rules.Bench...), c.StringSlice("bench")...)
+
+	plan := cwlPlan{round: round, rounds: len(g.group.Rounds), minWars: minWars, teamSize: c.Int("size")}
+
+	// Find the clan's roster and the lineup size
+	members := make(map[string]*planMember)
+	for _, gc := range g.group.Clans {
+		if gc.Tag != tag {
+			continue
+		}
+		plan.clanName = gc.Name
+		for _, m := range gc.Members {
+			members[m.Tag] = &planMember{name: m.Name, tag: m.Tag, townHall: m.TownHallLevel}
+		}
+	}
+	if len(members) == 0 {
+		err := fmt.Errorf("clan %s has no roster in its Clan War League group", tag)
+		fmt.Println(err)
+		return err
+	}
+	for _, wars := range g.rounds {
+		if w, ok := clanWar(wars, tag); ok && plan.teamSize == 0 {
+			plan.teamSize = w.TeamSize
+		}
+	}
+	if plan.teamSize == 0 {
+		plan.teamSize = defaultCwlTeamSize
+	}
+
+	// Count the attacks of each member in the earlier rounds
+	for i := 0; i < round-1 && i < len(g.rounds); i++ {
+		w, ok := clanWar(g.rounds[i], tag)
+		if !ok || w.State == "preparation" {
+			continue
+		}
+		for _, wm := range w.Clan.Members {
+			m, ok := members[wm.Tag]
+			if !ok {
+				continue
+			}
+			m.played++
+			m.attacks += len(wm.Attacks)
+			if w.State == "warEnded" && len(wm.Attacks) == 0 {
+				m.missed++
+			}
+		}
+	}
+
+	remaining := plan.rounds - round + 1
+	for _, m := range members {
+		req := request.Player{Tag: m.tag}
+		p, err := req.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		m.townHall = p.TownHallLevel
+		m.heroes = getHeroes(p.Heroes)
+		m.strength = p.Strength()
+		m.alwaysIn = matchesPlayer(m.tag, m.name, alwaysIn)
+		m.benched = matchesPlayer(m.tag, m.name, bench)
+		plan.members = append(plan.members, *m)
+	}
+
+	plan.members = planLineup(plan.members, plan.teamSize, minWars, remaining)
+
+	fmt.Println(plan)
+
+	return nil
+}
+
+// planLineup ranks the members and selects the lineup for a round with the given team size, where each
+// member should play at least minWars of the rounds and there are remaining rounds left including this
+// one.  The members are returned in lineup order, strongest first, followed by those left out.
+func planLineup(members []planMember, teamSize int, minWars int, remaining int) []planMember {
+	members = append([]planMember{}, members...)
+	for i := range members {
+		members[i].mustPlay = minWars-members[i].played >= remaining
+	}
+
+	// Always-in members come first, then those who need the remaining rounds to reach the minimum number
+	// of wars, then those who haven't missed an attack.  Within each group members are ranked by town hall,
+	// then those who have played fewer rounds come first so members of a similar strength take turns, and
+	// then the strongest come first.
+	rank := func(m planMember) int {
+		switch {
+		case m.alwaysIn:
+			return 0
+		case m.mustPlay:
+			return 1
+		case m.missed == 0:
+			return 2
+		}
+		return 3
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a.townHall != b.townHall {
+			return a.townHall > b.townHall
+		}
+		if a.played != b.played {
+			return a.played < b.played
+		}
+		if a.strength != b.strength {
+			return a.strength > b.strength
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+
+	selected := 0
+	for i := range members {
+		m := &members[i]
+		switch {
+		case m.benched && !m.alwaysIn:
+			m.reason = reasonBenched
+		case selected >= teamSize:
+			m.reason = reasonFull
+			if m.missed > 0 {
+				m.reason = reasonMissed
+			}
+		default:
+			m.selected = true
+			selected++
+			switch rank(*m) {
+			case 0:
+				m.reason = reasonAlwaysIn
+			case 1:
+				m.reason = reasonMinWars
+			default:
+				m.reason = reasonStrength
+			}
+		}
+	}
+
+	// Show the lineup in map order, strongest first, followed by those left out
+	sort.SliceStable(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.selected != b.selected {
+			return a.selected
+		}
+		if a.selected {
+			return a.strength > b.strength
+		}
+		return false
+	})
+
+	return members
+}
+
+// String returns a string representation of a planned CWL lineup
+func (p cwlPlan) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+
+	title := fmt.Sprintf("%s CWL round %d of %d lineup (%d members", p.clanName, p.round, p.rounds, p.teamSize)
+	if p.minWars > 0 {
+		title += ", " + strconv.Itoa(p.minWars) + " wars each"
+	}
+	t.SetTitle(title + ")")
+	t.AppendHeader(table.Row{"#", "Name", "TH", "BK", "AQ", "GW", "RC", "Played", "Attacks", "In", "Reason"})
+	n := 0
+	for _, m := range p.members {
+		pos := ""
+		in := "no"
+		if m.selected {
+			n++
+			pos = strconv.Itoa(n)
+			in = "yes"
+		}
+		attacks := strconv.Itoa(m.attacks)
+		if m.missed > 0 {
+			attacks += " (" + strconv.Itoa(m.missed) + " missed)"
+		}
+		t.AppendRow(table.Row{
+			pos, m.name, m.townHall, m.heroes.bk, m.heroes.aq, m.heroes.gw, m.heroes.rc, m.played, attacks, in, m.reason,
+		})
+	}
+
+	return t.Render()
+}
@@ -0,0 +1,97 @@
+package cmd2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlanLineup(t *testing.T) {
+	member := func(name string, townHall int, strength float64, played int) planMember {
+		return planMember{name: name, tag: "#" + strings.ToUpper(name), townHall: townHall, strength: strength, played: played}
+	}
+	alwaysIn := func(m planMember) planMember { m.alwaysIn = true; return m }
+	benched := func(m planMember) planMember { m.benched = true; return m }
+	missed := func(m planMember) planMember { m.missed = 1; return m }
+
+	tests := []struct {
+		name      string
+		members   []planMember
+		teamSize  int
+		minWars   int
+		remaining int
+		want      []string // Names of the selected members, in lineup order
+		reasons   map[string]string
+	}{
+		{
+			name:      "strongest town halls",
+			members:   []planMember{member("a", 14, 1300, 0), member("b", 15, 1450, 0), member("c", 13, 1280, 0)},
+			teamSize:  2,
+			remaining: 7,
+			want:      []string{"b", "a"},
+			reasons:   map[string]string{"a": reasonStrength, "c": reasonFull},
+		},
+		{
+			name:      "bench beats strength and always in beats bench",
+			members:   []planMember{benched(member("a", 15, 1500, 0)), alwaysIn(benched(member("b", 12, 1150, 0))), member("c", 13, 1280, 0)},
+			teamSize:  2,
+			remaining: 7,
+			want:      []string{"c", "b"},
+			reasons:   map[string]string{"a": reasonBenched, "b": reasonAlwaysIn},
+		},
+		{
+			name:      "always in takes a place ahead of stronger members",
+			members:   []planMember{member("a", 15, 1500, 0), alwaysIn(member("b", 12, 1150, 0))},
+			teamSize:  1,
+			remaining: 7,
+			want:      []string{"b"},
+			reasons:   map[string]string{"a": reasonFull},
+		},
+		{
+			name:      "must play overrides strength",
+			members:   []planMember{member("a", 15, 1500, 5), member("b", 13, 1280, 1)},
+			teamSize:  1,
+			minWars:   3,
+			remaining: 2,
+			want:      []string{"b"},
+			reasons:   map[string]string{"b": reasonMinWars, "a": reasonFull},
+		},
+		{
+			name:      "members who missed attacks come last",
+			members:   []planMember{missed(member("a", 15, 1500, 2)), member("b", 13, 1280, 2)},
+			teamSize:  1,
+			remaining: 5,
+			want:      []string{"b"},
+			reasons:   map[string]string{"a": reasonMissed},
+		},
+		{
+			name: "members of the same town hall take turns",
+			members: []planMember{
+				member("a", 14, 1390, 3), member("b", 14, 1310, 2), member("c", 14, 1350, 2), member("d", 15, 1420, 3),
+			},
+			teamSize:  3,
+			remaining: 4,
+			want:      []string{"d", "c", "b"},
+			reasons:   map[string]string{"a": reasonFull},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lineup := planLineup(tt.members, tt.teamSize, tt.minWars, tt.remaining)
+			if len(lineup) != len(tt.members) {
+				t.Fatalf("got %d members, want %d", len(lineup), len(tt.members))
+			}
+			var got []string
+			for _, m := range lineup {
+				if m.selected {
+					got = append(got, m.name)
+				}
+				if want, ok := tt.reasons[m.name]; ok && m.reason != want {
+					t.Errorf("%s reason = %q, want %q", m.name, m.reason, want)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("lineup = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package cmd2
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/logging"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultDaemonLogFile is the log file used by the daemon when none is configured
+	defaultDaemonLogFile = "coc-daemon.log"
+	// defaultDaemonLogLevel is the log level used by the daemon when none is configured
+	defaultDaemonLogLevel = "Info"
+
+	// retryInterval is how long to wait before polling again after a failure
+	retryInterval = 5 * time.Minute
+	// notInWarInterval is how often to poll for a new war when the clan isn't in one
+	notInWarInterval = 30 * time.Minute
+	// preparationInterval is how often to poll during preparation day
+	preparationInterval = time.Hour
+	// battleInterval is how often to poll during battle day, so attacks are saved as they are made
+	battleInterval = 10 * time.Minute
+	// endedGrace is how long after a war ends to poll for its final state
+	endedGrace = time.Minute
+)
+
+// daemonClan is the polling schedule for a clan
+type daemonClan struct {
+	tag         string                // Tag of the clan
+	nextWar     time.Time             // Time to next poll the clan's wars
+	nextMembers time.Time             // Time to next poll the clan's members
+	nextPlayers time.Time             // Time to next poll the profiles of the clan's members
+	members     []response.ClanMember // Members found by the latest poll of the clan's members
+}
+
+// Daemon polls the wars, members and players of the configured clans and saves them in the archive
+// until it is stopped with SIGINT or SIGTERM
+func Daemon(c *cli.Context) error {
+	// Get the clans to poll
+	tags := c.StringSlice("clan")
+	if len(tags) == 0 {
+		tags = config.Data.Clans
+	}
+	if len(tags) == 0 {
+		err := fmt.Errorf("no clans to poll, use the --clan option or add clans to the configuration")
+		fmt.Println(err)
+		return err
+	}
+
+	// Log to the daemon's log file
+	logFile := config.Data.Log.Daemon.File
+	if logFile == "" {
+		logFile = defaultDaemonLogFile
+	}
+	logLevel := config.Data.Log.Daemon.Level
+	if logLevel == "" {
+		logLevel = defaultDaemonLogLevel
+	}
+	logging.InitializeLogger(config.Data.Log.Dir, logFile, logLevel)
+
+	membersInterval := c.Duration("members-interval")
+	playersInterval := c.Duration("players-interval")
+
+	// Stop once the current poll completes, so a snapshot is never half written
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	var clans []*daemonClan
+	for _, tag := range tags {
+		clans = append(clans, &daemonClan{tag: response.NormalizeTag(tag)})
+	}
+	log.Info("daemon started, clans=", tags)
+
+	for {
+		now := time.Now()
+		next := now.Add(notInWarInterval)
+		for _, dc := range clans {
+			if !now.Before(dc.nextWar) {
+				dc.nextWar = now.Add(pollWars(c, dc.tag))
+			}
+			// The players polled are the members found by the latest poll of the members
+			membersDue := !now.Before(dc.nextMembers)
+			playersDue := !now.Before(dc.nextPlayers)
+			if membersDue || (playersDue && dc.members == nil) {
+				members, err := pollMembers(c, dc.tag)
+				if err == nil {
+					dc.members = members
+				}
+				if membersDue {
+					dc.nextMembers = now.Add(membersInterval)
+					if err != nil {
+						dc.nextMembers = now.Add(retryInterval)
+					}
+				}
+			}
+			if playersDue {
+				dc.nextPlayers = now.Add(playersInterval)
+				if err := pollPlayers(c, dc.tag, dc.members); err != nil {
+					dc.nextPlayers = now.Add(retryInterval)
+				}
+			}
+
+			for _, t := range []time.Time{dc.nextWar, dc.nextMembers, dc.nextPlayers} {
+				if t.Before(next) {
+					next = t
+				}
+			}
+		}
+
+		log.Debug("next poll at ", next)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case sig := <-stop:
+			timer.Stop()
+			log.Info("daemon stopped, signal=", sig)
+			return nil
+		}
+	}
+}
+
+// pollWars saves the clan's wars in the archive and returns how long to wait before polling them again
+func pollWars(c *cli.Context, tag string) time.Duration {
+	a, err := OpenArchive(c)
+	if err != nil {
+		return retryInterval
+	}
+	defer a.Close()
+
+	wars, saved, err := archiveWars(a, tag)
+	if err != nil {
+		log.Error("failed to save the wars for ", tag, ": ", err)
+		if len(wars) == 0 {
+			return retryInterval
+		}
+	}
+	log.Info("saved ", saved, " war snapshots for ", tag)
+
+	return warPollInterval(wars, time.Now())
+}
+
+// warPollInterval returns how long to wait before polling wars again, based on the phase of each war.
+// Polls are more frequent during battle day, and are timed to catch the start and end of each war.
+func warPollInterval(wars []archive.War, now time.Time) time.Duration {
+	interval := notInWarInterval
+	for _, w := range wars {
+		cw := w.ClanWar()
+		var d time.Duration
+		switch cw.State {
+		case "preparation":
+			d = preparationInterval
+			if start := getTime(cw.StartTime).Add(endedGrace).Sub(now); start > 0 && start < d {
+				d = start
+			}
+		case "inWar":
+			d = battleInterval
+			if end := getTime(cw.EndTime).Add(endedGrace).Sub(now); end > 0 && end < d {
+				d = end
+			}
+		default:
+			continue
+		}
+		if d < interval {
+			interval = d
+		}
+	}
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	return interval
+}
+
+// pollMembers saves the clan's members in the archive and returns them
+func pollMembers(c *cli.Context, tag string) ([]response.ClanMember, error) {
+	req := request.ClanMembers{Tag: tag}
+	members, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the members for ", tag, ": ", err)
+		return nil, err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return members, err
+	}
+	defer a.Close()
+
+	saved, err := a.PutMembers(archive.Members{ClanTag: tag, Members: members})
+	if err != nil {
+		log.Error("failed to save the members for ", tag, ": ", err)
+		return members, err
+	}
+	log.Info("polled ", len(members), " members for ", tag, ", saved=", saved)
+
+	return members, nil
+}
+
+// pollPlayers saves the profiles of the clan's members in the archive.  A player who can't be retrieved
+// is skipped so the others are still saved, and an error is only returned if none could be.
+func pollPlayers(c *cli.Context, tag string, members []response.ClanMember) error {
+	if len(members) == 0 {
+		err := fmt.Errorf("no members of clan %s to poll", tag)
+		log.Error(err)
+		return err
+	}
+
+	// Fetch the players before opening the archive, so it isn't held while waiting on the server
+	var players []archive.Player
+	var failed error
+	for _, m := range members {
+		preq := request.Player{Tag: m.Tag}
+		p, err := preq.Get()
+		if err != nil {
+			log.Error("failed to get the player ", m.Tag, ": ", err)
+			failed = err
+			continue
+		}
+		players = append(players, archive.Player{Saved: time.Now(), Player: p})
+	}
+	if len(players) == 0 {
+		return failed
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	saved := 0
+	for _, p := range players {
+		ok, err := a.PutPlayer(p)
+		if err != nil {
+			log.Error("failed to save the player ", p.Player.Tag, ": ", err)
+			return err
+		}
+		if ok {
+			saved++
+		}
+	}
+	log.Info("polled ", len(players), " players for ", tag, ", saved=", saved)
+
+	return nil
+}
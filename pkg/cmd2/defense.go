@@ -0,0 +1,202 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// defenseStats are a member's defense statistics over a set of wars
+type defenseStats struct {
+	name     string               // Name of the member
+	tag      string               // Tag of the member
+	townHall int                  // Town hall level in the most recent war
+	wars     int                  // Number of wars the member was in
+	total    attackStats          // All attacks against the member
+	byTH     map[int]*attackStats // Attacks against the member by the town hall level of the attacker
+}
+
+// defenseStatsList is the list of defense statistics for a clan
+type defenseStatsList struct {
+	clanName  string          // Name of the clan
+	wars      int             // Number of wars the statistics cover
+	townHalls []int           // Town hall levels of the attackers, highest first
+	members   []*defenseStats // Statistics for each member
+}
+
+// defenseStatsSorts are the columns the defense statistics may be sorted by, with the best first
+var defenseStatsSorts = map[string]func(a, b *defenseStats) bool{
+	"name":        func(a, b *defenseStats) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) },
+	"th":          func(a, b *defenseStats) bool { return a.townHall > b.townHall },
+	"wars":        func(a, b *defenseStats) bool { return a.wars > b.wars },
+	"attacks":     func(a, b *defenseStats) bool { return a.total.attacks > b.total.attacks },
+	"holdrate":    func(a, b *defenseStats) bool { return a.total.holdRate() > b.total.holdRate() },
+	"stars":       func(a, b *defenseStats) bool { return a.total.averageStars() < b.total.averageStars() },
+	"destruction": func(a, b *defenseStats) bool { return a.total.averageDestruction() < b.total.averageDestruction() },
+}
+
+// holdRate returns the percentage of attacks that earned two stars or fewer
+func (s attackStats) holdRate() float64 {
+	if s.attacks == 0 {
+		return 0
+	}
+	return float64(s.attacks-s.threeStars) * 100 / float64(s.attacks)
+}
+
+// getDefenseStats totals the attacks made against the clan's members in the wars that have ended,
+// keyed by the tag of the member.
+func getDefenseStats(wars []archive.War) map[string]*defenseStats {
+	stats := make(map[string]*defenseStats)
+	for _, w := range wars {
+		if w.State() != "warEnded" {
+			continue
+		}
+		cw := w.ClanWar()
+
+		for _, m := range cw.Clan.Members {
+			s, ok := stats[m.Tag]
+			if !ok {
+				s = &defenseStats{tag: m.Tag, byTH: make(map[int]*attackStats)}
+				stats[m.Tag] = s
+			}
+
+			// Wars are oldest first, so the latest name and town hall win
+			s.name = m.Name
+			s.townHall = m.TownhallLevel
+			s.wars++
+		}
+
+		for _, m := range cw.Opponent.Members {
+			for _, a := range m.Attacks {
+				s, ok := stats[a.DefenderTag]
+				if !ok {
+					continue
+				}
+				s.total.add(a.Stars, a.DestructionPercentage)
+				if s.byTH[m.TownhallLevel] == nil {
+					s.byTH[m.TownhallLevel] = &attackStats{}
+				}
+				s.byTH[m.TownhallLevel].add(a.Stars, a.DestructionPercentage)
+			}
+		}
+	}
+	return stats
+}
+
+// StatsDefense gets the defense statistics for each member over the archived wars
+func StatsDefense(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getDateRange(c)
+	if err != nil {
+		return err
+	}
+	sortBy := strings.ToLower(c.String("sort"))
+	less, ok := defenseStatsSorts[sortBy]
+	if !ok {
+		err := fmt.Errorf("invalid sort column %q", sortBy)
+		fmt.Println(err)
+		return err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	wars, err := a.Wars(tag, from, to)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+
+	list := defenseStatsList{}
+	for _, w := range wars {
+		if w.State() == "warEnded" {
+			list.wars++
+			list.clanName = w.ClanWar().Clan.Name
+		}
+	}
+	townHalls := make(map[int]bool)
+	for _, s := range getDefenseStats(wars) {
+		list.members = append(list.members, s)
+		for th := range s.byTH {
+			townHalls[th] = true
+		}
+	}
+	for th := range townHalls {
+		list.townHalls = append(list.townHalls, th)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(list.townHalls)))
+
+	// Sort by the requested column, then by name
+	reverse := c.Bool("reverse")
+	sort.Slice(list.members, func(i, j int) bool {
+		a, b := list.members[i], list.members[j]
+		if less(a, b) != less(b, a) {
+			return less(a, b) != reverse
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+
+	fmt.Println(list)
+
+	return nil
+}
+
+// getHoldRate returns a string representation of the hold rate for a set of attacks
+func getHoldRate(s *attackStats) string {
+	if s == nil || s.attacks == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% (%d)", s.holdRate(), s.attacks)
+}
+
+// String returns a string representation of the defense statistics
+func (l defenseStatsList) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	configs := []table.ColumnConfig{
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+		{Number: 7, Align: text.AlignRight},
+		{Number: 8, Align: text.AlignRight},
+	}
+	header := table.Row{"#", "Name", "TH", "Wars", "Attacks", "Hold", "Avg " + star, "Avg %"}
+	for i, th := range l.townHalls {
+		configs = append(configs, table.ColumnConfig{Number: 9 + i, Align: text.AlignRight})
+		header = append(header, "vs TH"+strconv.Itoa(th))
+	}
+	t.SetColumnConfigs(configs)
+
+	t.SetTitle(l.clanName + " defense statistics over " + strconv.Itoa(l.wars) + " wars")
+	t.AppendHeader(header)
+	for i, m := range l.members {
+		row := table.Row{i + 1, m.name, m.townHall, m.wars, m.total.attacks, "", "", ""}
+		if m.total.attacks > 0 {
+			row[5] = fmt.Sprintf("%.0f%%", m.total.holdRate())
+			row[6] = fmt.Sprintf("%.2f", m.total.averageStars())
+			row[7] = fmt.Sprintf("%.1f", m.total.averageDestruction())
+		}
+		for _, th := range l.townHalls {
+			row = append(row, getHoldRate(m.byTH[th]))
+		}
+		t.AppendRow(row)
+	}
+	t.SetCaption("Hold is the percentage of attacks held to two stars or fewer")
+
+	return t.Render()
+}
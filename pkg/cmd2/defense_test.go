@@ -0,0 +1,89 @@
+package cmd2
+
+import (
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+func TestGetDefenseStats(t *testing.T) {
+	first := &response.ClanWar{
+		State:                "warEnded",
+		PreparationStartTime: "20240101T000000.000Z",
+		Clan: response.ClanWarTeam{Tag: "#CLAN", Members: []response.ClanWarMember{
+			{Tag: "#A", Name: "a", TownhallLevel: 13},
+			{Tag: "#B", Name: "b", TownhallLevel: 12},
+		}},
+		Opponent: response.ClanWarTeam{Tag: "#OPP", Members: []response.ClanWarMember{
+			{Tag: "#X", TownhallLevel: 14, Attacks: []response.ClanWarAttack{
+				testAttack("#A", 3, 100),
+				testAttack("#B", 2, 90),
+			}},
+			{Tag: "#Y", TownhallLevel: 13, Attacks: []response.ClanWarAttack{
+				testAttack("#A", 1, 50),
+				testAttack("#NOTINCLAN", 3, 100),
+			}},
+		}},
+	}
+	// #A has upgraded and been renamed, #B sat out
+	second := &response.ClanWar{
+		State:                "warEnded",
+		PreparationStartTime: "20240102T000000.000Z",
+		Clan: response.ClanWarTeam{Tag: "#CLAN", Members: []response.ClanWarMember{
+			{Tag: "#A", Name: "a2", TownhallLevel: 14},
+		}},
+		Opponent: response.ClanWarTeam{Tag: "#OPP", Members: []response.ClanWarMember{
+			{Tag: "#X", TownhallLevel: 14, Attacks: []response.ClanWarAttack{testAttack("#A", 2, 70)}},
+		}},
+	}
+	// Wars that haven't ended are left out
+	inWar := *second
+	inWar.State = "inWar"
+	inWar.PreparationStartTime = "20240103T000000.000Z"
+	wars := []archive.War{
+		{ClanTag: "#CLAN", War: first},
+		{ClanTag: "#CLAN", War: second},
+		{ClanTag: "#CLAN", War: &inWar},
+	}
+
+	stats := getDefenseStats(wars)
+	if len(stats) != 2 {
+		t.Fatalf("got stats for %d members, want 2", len(stats))
+	}
+	tests := []struct {
+		tag      string
+		name     string
+		townHall int
+		wars     int
+		attacks  int
+		holdRate float64
+		byTH     map[int]int
+	}{
+		{"#A", "a2", 14, 2, 3, 100 * 2 / 3.0, map[int]int{14: 2, 13: 1}},
+		{"#B", "b", 12, 1, 1, 100, map[int]int{14: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			s := stats[tt.tag]
+			if s == nil {
+				t.Fatalf("no stats for %s", tt.tag)
+			}
+			if s.name != tt.name || s.townHall != tt.townHall || s.wars != tt.wars || s.total.attacks != tt.attacks {
+				t.Errorf("stats = %s TH%d, %d wars, %d attacks, want %s TH%d, %d wars, %d attacks",
+					s.name, s.townHall, s.wars, s.total.attacks, tt.name, tt.townHall, tt.wars, tt.attacks)
+			}
+			if s.total.holdRate() != tt.holdRate {
+				t.Errorf("hold rate = %v, want %v", s.total.holdRate(), tt.holdRate)
+			}
+			if len(s.byTH) != len(tt.byTH) {
+				t.Errorf("attacks by %d town halls, want %d", len(s.byTH), len(tt.byTH))
+			}
+			for th, n := range tt.byTH {
+				if s.byTH[th] == nil || s.byTH[th].attacks != n {
+					t.Errorf("attacks by TH%d = %v, want %d", th, s.byTH[th], n)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package cmd2
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultLeechRatio is the donation ratio below which a member is flagged, when none is configured
+	defaultLeechRatio = 0.5
+)
+
+// memberDonations are the troops donated and received by a member in a season
+type memberDonations struct {
+	name         string // Name of the member
+	tag          string // Tag of the member
+	role         string // Role of the member in the clan
+	donated      int    // Number of troops donated
+	received     int    // Number of troops received
+	lastDonated  int    // Donated count in the latest snapshot of the member
+	lastReceived int    // Received count in the latest snapshot of the member
+}
+
+// donationSeason is the donations made by a clan's members between two season resets
+type donationSeason struct {
+	start   time.Time                   // Time of the first snapshot in the season
+	end     time.Time                   // Time of the last snapshot in the season
+	reset   time.Time                   // Time of the first snapshot after the reset that started the season
+	after   time.Time                   // Time of the last snapshot before the reset that started the season
+	members map[string]*memberDonations // Donations of each member, keyed by tag
+}
+
+// donationsList is the donations of a clan's members in a season
+type donationsList struct {
+	title      string            // Title of the list
+	leechRatio float64           // Ratio below which a member is flagged as a leech
+	members    []memberDonations // Donations of each member, ranked by troops donated
+}
+
+// seasonList is the seasons of donations found in the archive
+type seasonList struct {
+	clanTag string           // Tag of the clan
+	seasons []donationSeason // Seasons, most recent first
+}
+
+// ratio returns the number of troops donated for each troop received
+func (d memberDonations) ratio() float64 {
+	if d.received == 0 {
+		if d.donated == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return float64(d.donated) / float64(d.received)
+}
+
+// isLeech returns true if the member received troops but donated less than the given ratio
+func (d memberDonations) isLeech(leechRatio float64) bool {
+	return d.received > 0 && d.ratio() < leechRatio
+}
+
+// totals returns the troops donated and received by all members in the season
+func (s donationSeason) totals() (int, int) {
+	var donated, received int
+	for _, m := range s.members {
+		donated += m.donated
+		received += m.received
+	}
+	return donated, received
+}
+
+// isSeasonReset returns true if the donation counts were reset between two snapshots.  The counts only
+// go up during a season, so a reset is when most members seen in both snapshots have lower counts.
+func isSeasonReset(prev []response.ClanMember, curr []response.ClanMember) bool {
+	before := make(map[string]response.ClanMember)
+	for _, m := range prev {
+		before[m.Tag] = m
+	}
+
+	common, lower := 0, 0
+	for _, m := range curr {
+		b, ok := before[m.Tag]
+		if !ok || b.Donations+b.DonationsReceived == 0 {
+			continue
+		}
+		common++
+		if m.Donations+m.DonationsReceived < b.Donations+b.DonationsReceived {
+			lower++
+		}
+	}
+	return common > 0 && lower*2 > common
+}
+
+// countIncrease returns the increase in a member's donation count between two snapshots.  A lower count
+// means the count restarted when the member rejoined the clan, so all of it is new.
+func countIncrease(prev int, curr int) int {
+	if curr < prev {
+		return curr
+	}
+	return curr - prev
+}
+
+// getDonationSeasons splits the snapshots of a clan's members into seasons at each reset of the donation
+// counts, and totals the donations of each member in each season.  Snapshots must be oldest first, and
+// the seasons are returned oldest first.  The first season starts at the first snapshot, so it may be
+// missing donations made before the archive was started.
+func getDonationSeasons(snapshots []archive.Members) []donationSeason {
+	var seasons []donationSeason
+	var prev []response.ClanMember
+	for i, s := range snapshots {
+		if i == 0 || isSeasonReset(prev, s.Members) {
+			season := donationSeason{start: s.Saved, members: make(map[string]*memberDonations)}
+			if i > 0 {
+				season.reset = s.Saved
+				season.after = snapshots[i-1].Saved
+			}
+			seasons = append(seasons, season)
+		}
+		season := &seasons[len(seasons)-1]
+		season.end = s.Saved
+
+		// Counts only go up during a season, except that they restart from zero when a member leaves and
+		// rejoins, so a drop adds the new count rather than the difference
+		for _, m := range s.Members {
+			d, ok := season.members[m.Tag]
+			if !ok {
+				d = &memberDonations{tag: m.Tag}
+				season.members[m.Tag] = d
+			}
+			d.name = m.Name
+			d.role = m.Role
+			d.donated += countIncrease(d.lastDonated, m.Donations)
+			d.received += countIncrease(d.lastReceived, m.DonationsReceived)
+			d.lastDonated, d.lastReceived = m.Donations, m.DonationsReceived
+		}
+		prev = s.Members
+	}
+	return seasons
+}
+
+// getLeechRatio gets the leech ratio from the `leech-ratio` option or, if that is not present, from the
+// configuration.  A ratio of zero turns the flag off.
+func getLeechRatio(c *cli.Context) float64 {
+	if c.IsSet("leech-ratio") {
+		return c.Float64("leech-ratio")
+	}
+	if config.Data.Donations.LeechRatio != nil {
+		return *config.Data.Donations.LeechRatio
+	}
+	return defaultLeechRatio
+}
+
+// Donations gets the troops donated and received by each member in the current season or, if the
+// `season` option is present, the totals for an earlier season from the snapshots in the archive
+func Donations(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	list := donationsList{leechRatio: getLeechRatio(c)}
+	season := c.Int("season")
+	if season < 0 {
+		err := fmt.Errorf("invalid season %d", season)
+		fmt.Println(err)
+		return err
+	}
+
+	if season == 0 && !c.Bool("seasons") {
+		req := request.ClanMembers{Tag: tag}
+		members, err := req.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		list.title = tag + " donations this season"
+		for _, m := range members {
+			list.members = append(list.members, memberDonations{
+				name:     m.Name,
+				tag:      m.Tag,
+				role:     m.Role,
+				donated:  m.Donations,
+				received: m.DonationsReceived,
+			})
+		}
+		list.rank()
+		fmt.Println(list)
+		return nil
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	snapshots, err := a.Members(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	seasons := getDonationSeasons(snapshots)
+
+	if c.Bool("seasons") {
+		l := seasonList{clanTag: tag}
+		for i := len(seasons) - 1; i >= 0; i-- {
+			l.seasons = append(l.seasons, seasons[i])
+		}
+		fmt.Println(l)
+		return nil
+	}
+
+	// The current season is the last one in the archive
+	if season >= len(seasons) {
+		err := fmt.Errorf("only %d seasons saved in the archive for %s", len(seasons), tag)
+		fmt.Println(err)
+		return err
+	}
+	s := seasons[len(seasons)-1-season]
+	list.title = fmt.Sprintf("%s donations from %s to %s", tag, s.start.Local().Format("2006-01-02"), s.end.Local().Format("2006-01-02"))
+	if s.reset.IsZero() {
+		list.title += " (partial)"
+	}
+	for _, d := range s.members {
+		list.members = append(list.members, *d)
+	}
+	list.rank()
+	fmt.Println(list)
+
+	return nil
+}
+
+// rank sorts the members by the number of troops donated, then by their ratio
+func (l donationsList) rank() {
+	sort.Slice(l.members, func(i, j int) bool {
+		a, b := l.members[i], l.members[j]
+		if a.donated != b.donated {
+			return a.donated > b.donated
+		}
+		if a.ratio() != b.ratio() {
+			return a.ratio() > b.ratio()
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+}
+
+// getRatio returns a string representation of a donation ratio
+func getRatio(d memberDonations) string {
+	r := d.ratio()
+	if math.IsInf(r, 1) {
+		return "∞"
+	}
+	return fmt.Sprintf("%.2f", r)
+}
+
+// String returns a string representation of the donations in a season
+func (l donationsList) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+	})
+
+	t.SetTitle(l.title)
+	t.AppendHeader(table.Row{"#", "Name", "Role", "Donated", "Received", "Ratio", "Leech"})
+	var donated, received, leeches int
+	for i, m := range l.members {
+		leech := ""
+		if m.isLeech(l.leechRatio) {
+			leech = "yes"
+			leeches++
+		}
+		t.AppendRow(table.Row{i + 1, m.name, getRoleName(m.role), m.donated, m.received, getRatio(m), leech})
+		donated += m.donated
+		received += m.received
+	}
+	t.AppendFooter(table.Row{"", "Total", "", donated, received, "", fmt.Sprintf("%d below %.2f", leeches, l.leechRatio)})
+
+	return t.Render()
+}
+
+// String returns a string representation of the seasons in the archive
+func (l seasonList) String() string {
+	const layout = "2006-01-02 15:04"
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+
+	t.SetTitle(l.clanTag + " donation seasons")
+	t.AppendHeader(table.Row{"Season", "Reset Between", "First Snapshot", "Last Snapshot", "Members", "Donated", "Received"})
+	for i, s := range l.seasons {
+		reset := "before the archive started"
+		if !s.reset.IsZero() {
+			reset = s.after.Local().Format(layout) + " and " + s.reset.Local().Format(layout)
+		}
+		donated, received := s.totals()
+		t.AppendRow(table.Row{
+			i, reset, s.start.Local().Format(layout), s.end.Local().Format(layout), len(s.members), donated, received,
+		})
+	}
+
+	return t.Render()
+}
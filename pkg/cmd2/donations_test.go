@@ -0,0 +1,107 @@
+package cmd2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testMember returns a clan member with the given donation counts
+func testMember(tag string, donated int, received int) response.ClanMember {
+	return response.ClanMember{Tag: tag, Name: tag, Donations: donated, DonationsReceived: received}
+}
+
+func TestIsSeasonReset(t *testing.T) {
+	tests := []struct {
+		name string
+		prev []response.ClanMember
+		curr []response.ClanMember
+		want bool
+	}{
+		{
+			name: "counts went up",
+			prev: []response.ClanMember{testMember("#A", 10, 5), testMember("#B", 3, 3)},
+			curr: []response.ClanMember{testMember("#A", 12, 5), testMember("#B", 3, 4)},
+		},
+		{
+			name: "most counts dropped",
+			prev: []response.ClanMember{testMember("#A", 10, 5), testMember("#B", 3, 3), testMember("#C", 1, 0)},
+			curr: []response.ClanMember{testMember("#A", 0, 0), testMember("#B", 1, 0), testMember("#C", 2, 0)},
+			want: true,
+		},
+		{
+			name: "one member rejoined",
+			prev: []response.ClanMember{testMember("#A", 10, 5), testMember("#B", 3, 3), testMember("#C", 1, 0)},
+			curr: []response.ClanMember{testMember("#A", 0, 0), testMember("#B", 4, 3), testMember("#C", 2, 0)},
+		},
+		{
+			name: "members with no donations are ignored",
+			prev: []response.ClanMember{testMember("#A", 0, 0), testMember("#B", 3, 3)},
+			curr: []response.ClanMember{testMember("#A", 0, 0), testMember("#B", 0, 0)},
+			want: true,
+		},
+		{
+			name: "no members in common",
+			prev: []response.ClanMember{testMember("#A", 10, 5)},
+			curr: []response.ClanMember{testMember("#B", 0, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isSeasonReset(tt.prev, tt.curr); got != tt.want {
+				t.Errorf("isSeasonReset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDonationSeasons(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []archive.Members{
+		{Saved: day(1), Members: []response.ClanMember{testMember("#A", 10, 2), testMember("#B", 5, 5), testMember("#C", 4, 4)}},
+		// #B leaves
+		{Saved: day(2), Members: []response.ClanMember{testMember("#A", 20, 4), testMember("#C", 6, 4)}},
+		// #B rejoins with counts that restarted from zero
+		{Saved: day(3), Members: []response.ClanMember{testMember("#A", 25, 4), testMember("#B", 2, 1), testMember("#C", 8, 6)}},
+		{Saved: day(4), Members: []response.ClanMember{testMember("#A", 30, 6), testMember("#B", 6, 1), testMember("#C", 8, 6)}},
+		// Season reset
+		{Saved: day(5), Members: []response.ClanMember{testMember("#A", 1, 0), testMember("#B", 0, 0), testMember("#C", 0, 2)}},
+	}
+
+	seasons := getDonationSeasons(snapshots)
+	if len(seasons) != 2 {
+		t.Fatalf("got %d seasons, want 2", len(seasons))
+	}
+	if !seasons[0].start.Equal(day(1)) || !seasons[0].end.Equal(day(4)) || !seasons[0].reset.IsZero() {
+		t.Errorf("first season %v to %v, reset %v", seasons[0].start, seasons[0].end, seasons[0].reset)
+	}
+	if !seasons[1].reset.Equal(day(5)) || !seasons[1].after.Equal(day(4)) {
+		t.Errorf("second season reset %v after %v", seasons[1].reset, seasons[1].after)
+	}
+
+	tests := []struct {
+		season   int
+		tag      string
+		donated  int
+		received int
+	}{
+		{0, "#A", 30, 6},
+		{0, "#B", 11, 6}, // 5 before leaving and 6 after rejoining
+		{0, "#C", 8, 6},
+		{1, "#A", 1, 0},
+		{1, "#C", 0, 2},
+	}
+	for _, tt := range tests {
+		d := seasons[tt.season].members[tt.tag]
+		if d == nil {
+			t.Errorf("season %d has no donations for %s", tt.season, tt.tag)
+			continue
+		}
+		if d.donated != tt.donated || d.received != tt.received {
+			t.Errorf("season %d %s donated %d and received %d, want %d and %d",
+				tt.season, tt.tag, d.donated, d.received, tt.donated, tt.received)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+package cmd2
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// minForecastSamples is the number of past attacks a member needs against a town hall level before
+	// their own attacks are used to forecast their attacks against it
+	minForecastSamples = 3
+)
+
+// attackOutcome is the result of a single attack
+type attackOutcome struct {
+	stars       int // Stars earned
+	destruction int // Destruction percentage
+}
+
+// attackHistory are past attack outcomes, used to draw the outcomes of the attacks left in a war
+type attackHistory struct {
+	members map[string]map[int][]attackOutcome // Outcomes for each member, keyed by the defender's town hall
+	gaps    map[int][]attackOutcome            // Outcomes for all attackers, keyed by the defender's town hall less the attacker's
+}
+
+// priorOutcomes are the outcomes drawn from when there is no history for an attack, keyed by the sign of
+// the defender's town hall less the attacker's
+var priorOutcomes = map[int][]attackOutcome{
+	-1: {{3, 100}, {3, 100}, {3, 100}, {2, 90}},
+	0:  {{3, 100}, {2, 85}, {2, 70}, {1, 55}},
+	1:  {{2, 75}, {2, 60}, {1, 50}, {1, 40}},
+}
+
+// forecastAttacker is a member of a war with attacks left
+type forecastAttacker struct {
+	tag      string // Tag of the member
+	townHall int    // Town hall level
+	left     int    // Number of attacks left
+}
+
+// forecastBase is a base in a war, with the best attack against it so far
+type forecastBase struct {
+	townHall    int // Town hall level
+	stars       int // Best stars against the base
+	destruction int // Best destruction percentage against the base
+}
+
+// forecastSide is one clan in a war being forecast
+type forecastSide struct {
+	name        string             // Name of the clan
+	tag         string             // Tag of the clan
+	attackers   []forecastAttacker // Members with attacks left, in map order
+	targets     []forecastBase     // The opponent's bases, in map order
+	left        int                // Number of attacks left
+	stars       int                // Stars so far
+	destruction float64            // Destruction percentage so far
+	projStars   float64            // Average stars at the end of the simulated wars
+	projDestr   float64            // Average destruction percentage at the end of the simulated wars
+}
+
+// warForecast is the projected result of a war
+type warForecast struct {
+	endTime  time.Time    // The time the war ends
+	state    string       // State of the war
+	runs     int          // Number of simulated wars
+	wars     int          // Number of archived wars the attack history comes from
+	clan     forecastSide // The clan
+	opponent forecastSide // The clan's opponent
+	wins     int          // Number of simulated wars the clan won
+	ties     int          // Number of simulated wars that were tied
+}
+
+// getAttackHistory collects the outcomes of the attacks made by the clan's members in the wars that
+// have ended
+func getAttackHistory(wars []archive.War) attackHistory {
+	h := attackHistory{members: make(map[string]map[int][]attackOutcome), gaps: make(map[int][]attackOutcome)}
+	for _, w := range wars {
+		if w.State() != "warEnded" {
+			continue
+		}
+		h.addWar(w.ClanWar().Clan, w.ClanWar().Opponent, true)
+	}
+	return h
+}
+
+// addWar adds the attacks made by a clan against its opponent.  The outcomes are only kept for each
+// member if byMember is true.
+func (h attackHistory) addWar(clan response.ClanWarTeam, opponent response.ClanWarTeam, byMember bool) {
+	defenderTH := make(map[string]int)
+	for _, m := range opponent.Members {
+		defenderTH[m.Tag] = m.TownhallLevel
+	}
+
+	for _, m := range clan.Members {
+		for _, a := range m.Attacks {
+			// Attacks on a defender whose town hall isn't known can't be matched to a town hall
+			th, ok := defenderTH[a.DefenderTag]
+			if !ok || th == 0 {
+				continue
+			}
+			o := attackOutcome{stars: a.Stars, destruction: a.DestructionPercentage}
+			if byMember {
+				if h.members[m.Tag] == nil {
+					h.members[m.Tag] = make(map[int][]attackOutcome)
+				}
+				h.members[m.Tag][th] = append(h.members[m.Tag][th], o)
+			}
+			h.gaps[th-m.TownhallLevel] = append(h.gaps[th-m.TownhallLevel], o)
+		}
+	}
+}
+
+// outcomes returns the outcomes to draw from for an attack by the attacker on a base with the given
+// town hall level.  The attacker's own history is used if there is enough of it, then the history of
+// all attackers for the same town hall gap, and then the prior outcomes.
+func (h attackHistory) outcomes(a forecastAttacker, townHall int) []attackOutcome {
+	if o := h.members[a.tag][townHall]; len(o) >= minForecastSamples {
+		return o
+	}
+	gap := townHall - a.townHall
+	if o := h.gaps[gap]; len(o) > 0 {
+		return o
+	}
+	switch {
+	case gap < 0:
+		return priorOutcomes[-1]
+	case gap > 0:
+		return priorOutcomes[1]
+	}
+	return priorOutcomes[0]
+}
+
+// getForecastSide gets the attackers of a clan with attacks left and the bases of its opponent, where
+// each member has the given number of attacks in the war
+func getForecastSide(clan response.ClanWarTeam, opponent response.ClanWarTeam, attacksPerMember int) forecastSide {
+	s := forecastSide{name: clan.Name, tag: clan.Tag, stars: clan.Stars, destruction: float64(clan.DestructionPercentage)}
+
+	members := append([]response.ClanWarMember{}, clan.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].MapPosition < members[j].MapPosition })
+	for _, m := range members {
+		if left := attacksPerMember - len(m.Attacks); left > 0 {
+			s.attackers = append(s.attackers, forecastAttacker{tag: m.Tag, townHall: m.TownhallLevel, left: left})
+			s.left += left
+		}
+	}
+
+	bases := append([]response.ClanWarMember{}, opponent.Members...)
+	sort.Slice(bases, func(i, j int) bool { return bases[i].MapPosition < bases[j].MapPosition })
+	for _, m := range bases {
+		s.targets = append(s.targets, forecastBase{
+			townHall:    m.TownhallLevel,
+			stars:       m.BestOpponentAttack.Stars,
+			destruction: m.BestOpponentAttack.DestructionPercentage,
+		})
+	}
+	return s
+}
+
+// simulate plays out the attacks left for a clan once, returning the final stars and destruction.  Each
+// attacker, strongest first, hits the highest base of their town hall level or lower that isn't cleared,
+// or the lowest base that isn't cleared if there is none.
+func (s forecastSide) simulate(h attackHistory, teamSize int, r *rand.Rand) (int, float64) {
+	targets := append([]forecastBase{}, s.targets...)
+	stars := s.stars
+	destruction := s.destruction * float64(teamSize)
+
+	attackers := append([]forecastAttacker{}, s.attackers...)
+	sort.SliceStable(attackers, func(i, j int) bool { return attackers[i].townHall > attackers[j].townHall })
+	for _, a := range attackers {
+		for n := 0; n < a.left; n++ {
+			target := -1
+			for i, t := range targets {
+				if t.stars == 3 {
+					continue
+				}
+				if t.townHall <= a.townHall {
+					target = i
+					break
+				}
+				target = i
+			}
+			if target < 0 {
+				break
+			}
+
+			t := &targets[target]
+			outcomes := h.outcomes(a, t.townHall)
+			o := outcomes[r.Intn(len(outcomes))]
+			if o.stars > t.stars {
+				stars += o.stars - t.stars
+				t.stars = o.stars
+			}
+			if o.destruction > t.destruction {
+				destruction += float64(o.destruction - t.destruction)
+				t.destruction = o.destruction
+			}
+		}
+	}
+
+	return stars, destruction / float64(teamSize)
+}
+
+// WarForecast projects the result of the current war by simulating the attacks left for both clans,
+// using the historical outcomes of the clan's attacks from the archive
+func WarForecast(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getDateRange(c)
+	if err != nil {
+		return err
+	}
+	runs := c.Int("runs")
+	if runs < 1 {
+		err := fmt.Errorf("invalid number of runs %d", runs)
+		fmt.Println(err)
+		return err
+	}
+
+	war, attacksPerMember, err := getCurrentWarAttacks(c, tag)
+	if err != nil {
+		return err
+	}
+	if war.State == "notInWar" || war.State == "" {
+		err := fmt.Errorf("clan %s is not in a war", tag)
+		fmt.Println(err)
+		return err
+	}
+	if war.Clan.Tag != tag {
+		war.Clan, war.Opponent = war.Opponent, war.Clan
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	wars, err := a.Wars(tag, from, to)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+
+	// The opponent has no history, so its attackers are forecast from the attacks it has made in this
+	// war and the history of the clan's attackers
+	history := getAttackHistory(wars)
+	opponentHistory := attackHistory{members: make(map[string]map[int][]attackOutcome), gaps: make(map[int][]attackOutcome)}
+	for gap, o := range history.gaps {
+		opponentHistory.gaps[gap] = append([]attackOutcome{}, o...)
+	}
+	opponentHistory.addWar(war.Opponent, war.Clan, false)
+
+	f := warForecast{
+		endTime:  getTime(war.EndTime),
+		state:    war.State,
+		runs:     runs,
+		clan:     getForecastSide(war.Clan, war.Opponent, attacksPerMember),
+		opponent: getForecastSide(war.Opponent, war.Clan, attacksPerMember),
+	}
+	for _, w := range wars {
+		if w.State() == "warEnded" {
+			f.wars++
+		}
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < runs; i++ {
+		clanStars, clanDestr := f.clan.simulate(history, war.TeamSize, r)
+		opStars, opDestr := f.opponent.simulate(opponentHistory, war.TeamSize, r)
+		f.clan.projStars += float64(clanStars) / float64(runs)
+		f.clan.projDestr += clanDestr / float64(runs)
+		f.opponent.projStars += float64(opStars) / float64(runs)
+		f.opponent.projDestr += opDestr / float64(runs)
+		switch {
+		case clanStars > opStars, clanStars == opStars && clanDestr > opDestr:
+			f.wins++
+		case clanStars == opStars && clanDestr == opDestr:
+			f.ties++
+		}
+	}
+
+	fmt.Println(f)
+
+	return nil
+}
+
+// String returns a string representation of a war forecast
+func (f warForecast) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 1, Align: text.AlignRight},
+		{Number: 2, Align: text.AlignCenter},
+		{Number: 3, Align: text.AlignLeft},
+	})
+
+	t.AppendHeader(table.Row{f.clan.name, "", f.opponent.name})
+	t.AppendRow(table.Row{f.clan.stars, "stars", f.opponent.stars})
+	t.AppendRow(table.Row{fmt.Sprintf("%.1f", f.clan.projStars), "projected stars", fmt.Sprintf("%.1f", f.opponent.projStars)})
+	t.AppendRow(table.Row{fmt.Sprintf("%.1f", f.clan.destruction), "%", fmt.Sprintf("%.1f", f.opponent.destruction)})
+	t.AppendRow(table.Row{fmt.Sprintf("%.1f", f.clan.projDestr), "projected %", fmt.Sprintf("%.1f", f.opponent.projDestr)})
+	t.AppendRow(table.Row{f.clan.left, "attacks left", f.opponent.left})
+
+	losses := f.runs - f.wins - f.ties
+	odds := fmt.Sprintf("Win %.1f%%, tie %.1f%%, lose %.1f%% over %d runs",
+		float64(f.wins)*100/float64(f.runs), float64(f.ties)*100/float64(f.runs), float64(losses)*100/float64(f.runs), f.runs)
+	caption := odds + "\nFrom " + strconv.Itoa(f.wars) + " archived wars"
+	if f.state != "warEnded" {
+		caption += "\n" + getTimeLeft(f.endTime)
+	}
+	t.SetCaption("%s", caption)
+
+	return t.Render()
+}
@@ -0,0 +1,118 @@
+package cmd2
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAttackHistoryOutcomes(t *testing.T) {
+	member := []attackOutcome{{3, 100}, {3, 100}, {2, 90}}
+	gap := []attackOutcome{{1, 60}}
+	h := attackHistory{
+		members: map[string]map[int][]attackOutcome{
+			"#A": {14: member, 13: member[:2]},
+		},
+		gaps: map[int][]attackOutcome{0: gap},
+	}
+
+	tests := []struct {
+		name     string
+		attacker forecastAttacker
+		townHall int
+		want     []attackOutcome
+	}{
+		{"member history", forecastAttacker{tag: "#A", townHall: 13}, 14, member},
+		{"too little member history uses the gap", forecastAttacker{tag: "#A", townHall: 13}, 13, gap},
+		{"no member history uses the gap", forecastAttacker{tag: "#B", townHall: 12}, 12, gap},
+		{"lower town hall prior", forecastAttacker{tag: "#B", townHall: 12}, 11, priorOutcomes[-1]},
+		{"higher town hall prior", forecastAttacker{tag: "#A", townHall: 13}, 15, priorOutcomes[1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.outcomes(tt.attacker, tt.townHall)
+			if len(got) != len(tt.want) || (len(got) > 0 && &got[0] != &tt.want[0]) {
+				t.Errorf("outcomes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForecastSideSimulate(t *testing.T) {
+	h := attackHistory{gaps: map[int][]attackOutcome{
+		0: {{3, 100}},
+		1: {{1, 50}},
+		2: {{1, 90}},
+	}}
+
+	tests := []struct {
+		name        string
+		side        forecastSide
+		teamSize    int
+		stars       int
+		destruction float64
+	}{
+		{
+			name: "equal town hall first, then the lowest base left",
+			side: forecastSide{
+				attackers:   []forecastAttacker{{tag: "#A", townHall: 13, left: 2}},
+				targets:     []forecastBase{{townHall: 14}, {townHall: 13}, {townHall: 12, stars: 3, destruction: 100}},
+				stars:       3,
+				destruction: 100.0 / 3,
+			},
+			teamSize:    3,
+			stars:       7,
+			destruction: 250.0 / 3,
+		},
+		{
+			name: "only improvements count",
+			side: forecastSide{
+				attackers:   []forecastAttacker{{tag: "#A", townHall: 12, left: 1}},
+				targets:     []forecastBase{{townHall: 14, stars: 2, destruction: 80}},
+				stars:       2,
+				destruction: 80,
+			},
+			teamSize:    1,
+			stars:       2,
+			destruction: 90,
+		},
+		{
+			name: "nothing left to attack",
+			side: forecastSide{
+				attackers:   []forecastAttacker{{tag: "#A", townHall: 13, left: 2}},
+				targets:     []forecastBase{{townHall: 13, stars: 3, destruction: 100}},
+				stars:       3,
+				destruction: 100,
+			},
+			teamSize:    1,
+			stars:       3,
+			destruction: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stars, destruction := tt.side.simulate(h, tt.teamSize, rand.New(rand.NewSource(1)))
+			if stars != tt.stars || math.Abs(destruction-tt.destruction) > 0.001 {
+				t.Errorf("simulate = %d stars and %.2f%%, want %d and %.2f%%", stars, destruction, tt.stars, tt.destruction)
+			}
+		})
+	}
+
+	// The same seed plays out the same attacks
+	side := forecastSide{
+		attackers: []forecastAttacker{{tag: "#A", townHall: 13, left: 2}, {tag: "#B", townHall: 12, left: 2}},
+		targets:   []forecastBase{{townHall: 14}, {townHall: 13}, {townHall: 12}},
+	}
+	empty := attackHistory{}
+	s1, d1 := side.simulate(empty, 3, rand.New(rand.NewSource(42)))
+	s2, d2 := side.simulate(empty, 3, rand.New(rand.NewSource(42)))
+	if s1 != s2 || d1 != d2 {
+		t.Errorf("simulate with the same seed = %d and %.2f, then %d and %.2f", s1, d1, s2, d2)
+	}
+	if s1 < 3 || s1 > 9 || d1 <= 0 || d1 > 100 {
+		t.Errorf("simulate = %d stars and %.2f%%, out of range", s1, d1)
+	}
+	if side.targets[0].stars != 0 {
+		t.Error("simulate changed the side's targets")
+	}
+}
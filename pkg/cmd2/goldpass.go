@@ -0,0 +1,53 @@
+package cmd2
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// goldPassSeason is the window for a gold pass season
+type goldPassSeason struct {
+	start time.Time // The time the season started
+	end   time.Time // The time the season ends
+}
+
+// GoldPass gets the current gold pass season
+func GoldPass(c *cli.Context) error {
+	req := request.GoldPassSeason{}
+	s, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	season := goldPassSeason{start: s.Start(), end: s.End()}
+	fmt.Println(season)
+
+	return nil
+}
+
+// String returns a string representation of a gold pass season
+func (s goldPassSeason) String() string {
+	const layout = "Mon Jan 2 2006 15:04 MST"
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetTitle("Gold Pass Season")
+	t.AppendRow(table.Row{"Started", s.start.Local().Format(layout)})
+	t.AppendRow(table.Row{"Ends", s.end.Local().Format(layout)})
+
+	left := s.end.Sub(time.Now())
+	if left > 0 {
+		t.SetCaption("Season ends in \033[1m" + getDuration(left) + "\033[0m")
+	} else {
+		t.SetCaption("Season has ended")
+	}
+
+	return t.Render()
+}
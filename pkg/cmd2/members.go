@@ -0,0 +1,312 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// Kinds of member events
+	eventJoin       = "join"
+	eventRejoin     = "rejoin"
+	eventLeave      = "leave"
+	eventPromotion  = "promotion"
+	eventDemotion   = "demotion"
+	eventNameChange = "name"
+	eventTownHall   = "townhall"
+)
+
+// roleRanks orders the roles in a clan from lowest to highest
+var roleRanks = map[string]int{
+	"member":   1,
+	"admin":    2,
+	"coLeader": 3,
+	"leader":   4,
+}
+
+// roleNames are the names shown in the game for each role
+var roleNames = map[string]string{
+	"member":   "Member",
+	"admin":    "Elder",
+	"coLeader": "Co-leader",
+	"leader":   "Leader",
+}
+
+// memberEvent is a change in a clan's membership found between two snapshots of its members
+type memberEvent struct {
+	time   time.Time // Time of the snapshot in which the change was first seen
+	tag    string    // Tag of the member
+	name   string    // Name of the member
+	kind   string    // Kind of event
+	detail string    // Description of the change
+}
+
+// memberTenure is the time a player has spent in a clan
+type memberTenure struct {
+	tag       string        // Tag of the player
+	name      string        // Latest name of the player
+	names     []string      // All names the player has been seen with
+	firstSeen time.Time     // Time the player was first seen in the clan
+	lastSeen  time.Time     // Time the player was last seen in the clan
+	joined    time.Time     // Time the player last joined, if still in the clan
+	joins     int           // Number of times the player joined the clan, including the first time seen
+	present   bool          // The player is in the clan in the latest snapshot
+	tenure    time.Duration // Total time spent in the clan
+}
+
+// memberHistory is the membership history of a clan
+type memberHistory struct {
+	clanTag string                   // Tag of the clan
+	from    time.Time                // Time of the first snapshot
+	events  []memberEvent            // Changes in membership, oldest first
+	tenures map[string]*memberTenure // Tenure of each player, keyed by tag
+}
+
+// getRoleName returns the name shown in the game for a role
+func getRoleName(role string) string {
+	if name, ok := roleNames[role]; ok {
+		return name
+	}
+	return role
+}
+
+// getMemberHistory diffs consecutive snapshots of a clan's members into a list of events and the
+// tenure of each player.  Snapshots must be oldest first.  Changes are only seen when a snapshot is
+// taken, so the time of each event is the time of the first snapshot that shows it.
+func getMemberHistory(clanTag string, snapshots []archive.Members, now time.Time) memberHistory {
+	h := memberHistory{clanTag: clanTag, tenures: make(map[string]*memberTenure)}
+	if len(snapshots) == 0 {
+		return h
+	}
+	h.from = snapshots[0].Saved
+
+	prev := make(map[string]response.ClanMember)
+	for i, s := range snapshots {
+		curr := make(map[string]response.ClanMember)
+		for _, m := range s.Members {
+			curr[m.Tag] = m
+
+			t, ok := h.tenures[m.Tag]
+			if !ok {
+				t = &memberTenure{tag: m.Tag, firstSeen: s.Saved}
+				h.tenures[m.Tag] = t
+			}
+			t.name = m.Name
+			if len(t.names) == 0 || t.names[len(t.names)-1] != m.Name {
+				t.names = append(t.names, m.Name)
+			}
+			t.lastSeen = s.Saved
+
+			old, existed := prev[m.Tag]
+			if !existed {
+				t.joins++
+				t.joined = s.Saved
+				t.present = true
+				// Members in the first snapshot were already in the clan, so there is nothing to report
+				if i > 0 {
+					kind, detail := eventJoin, "Joined as "+getRoleName(m.Role)
+					if t.joins > 1 {
+						kind, detail = eventRejoin, "Rejoined as "+getRoleName(m.Role)
+					}
+					h.events = append(h.events, memberEvent{s.Saved, m.Tag, m.Name, kind, detail})
+				}
+				continue
+			}
+
+			if old.Role != m.Role {
+				kind := eventPromotion
+				if roleRanks[m.Role] < roleRanks[old.Role] {
+					kind = eventDemotion
+				}
+				detail := getRoleName(old.Role) + " to " + getRoleName(m.Role)
+				h.events = append(h.events, memberEvent{s.Saved, m.Tag, m.Name, kind, detail})
+			}
+			if old.Name != m.Name {
+				detail := old.Name + " to " + m.Name
+				h.events = append(h.events, memberEvent{s.Saved, m.Tag, m.Name, eventNameChange, detail})
+			}
+			// Older snapshots may not include the town hall level
+			if old.TownHallLevel > 0 && m.TownHallLevel > old.TownHallLevel {
+				detail := fmt.Sprintf("TH%d to TH%d", old.TownHallLevel, m.TownHallLevel)
+				h.events = append(h.events, memberEvent{s.Saved, m.Tag, m.Name, eventTownHall, detail})
+			}
+		}
+
+		for tag, m := range prev {
+			if _, ok := curr[tag]; ok {
+				continue
+			}
+			t := h.tenures[tag]
+			t.tenure += s.Saved.Sub(t.joined)
+			t.present = false
+			h.events = append(h.events, memberEvent{s.Saved, tag, m.Name, eventLeave, "Left as " + getRoleName(m.Role)})
+		}
+
+		prev = curr
+	}
+
+	// Players still in the clan have been there since they last joined
+	for _, t := range h.tenures {
+		if t.present {
+			t.tenure += now.Sub(t.joined)
+		}
+	}
+
+	// Events in the same snapshot are in map order, so order them by player as well
+	sort.SliceStable(h.events, func(i, j int) bool {
+		a, b := h.events[i], h.events[j]
+		if !a.time.Equal(b.time) {
+			return a.time.Before(b.time)
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+
+	return h
+}
+
+// matches returns true if the player has the given tag, or has ever had the given name
+func (t memberTenure) matches(player string) bool {
+	if strings.EqualFold(t.tag, player) || strings.EqualFold(t.tag, "#"+player) {
+		return true
+	}
+	for _, name := range t.names {
+		if strings.EqualFold(name, player) {
+			return true
+		}
+	}
+	return false
+}
+
+// MembersHistory gets the joins, leaves, role changes, name changes and town hall upgrades of the clan's
+// members from the snapshots in the archive, along with the tenure of each player
+func MembersHistory(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	var since time.Time
+	if s := c.String("since"); s != "" {
+		since, err = time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			err = fmt.Errorf("invalid since date %q, expected YYYY-MM-DD", s)
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	// Tenure covers every snapshot, so read them all and only filter the events
+	snapshots, err := a.Members(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	if len(snapshots) == 0 {
+		err := fmt.Errorf("no member snapshots saved for %s, run the daemon to save them", tag)
+		fmt.Println(err)
+		return err
+	}
+
+	h := getMemberHistory(tag, snapshots, time.Now())
+
+	player := c.String("player")
+	if player != "" {
+		found := false
+		for _, t := range h.tenures {
+			if t.matches(player) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			err := fmt.Errorf("player %q not found in the member snapshots for %s", player, tag)
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	var events []memberEvent
+	for _, e := range h.events {
+		if e.time.Before(since) {
+			continue
+		}
+		if player != "" && !h.tenures[e.tag].matches(player) {
+			continue
+		}
+		events = append(events, e)
+	}
+	h.events = events
+	if player != "" {
+		for tag, t := range h.tenures {
+			if !t.matches(player) {
+				delete(h.tenures, tag)
+			}
+		}
+	}
+
+	fmt.Println(h)
+
+	return nil
+}
+
+// String returns a string representation of the membership history
+func (h memberHistory) String() string {
+	const layout = "2006-01-02 15:04"
+
+	e := table.NewWriter()
+	e.SetStyle(table.StyleColoredBright)
+	e.SetTitle(h.clanTag + " member history since " + h.from.Local().Format(layout))
+	e.AppendHeader(table.Row{"Time", "Name", "Tag", "Event", "Detail"})
+	for _, ev := range h.events {
+		e.AppendRow(table.Row{ev.time.Local().Format(layout), ev.name, ev.tag, ev.kind, ev.detail})
+	}
+
+	// Longest serving first
+	var tenures []*memberTenure
+	for _, t := range h.tenures {
+		tenures = append(tenures, t)
+	}
+	sort.Slice(tenures, func(i, j int) bool {
+		if tenures[i].tenure != tenures[j].tenure {
+			return tenures[i].tenure > tenures[j].tenure
+		}
+		return strings.ToLower(tenures[i].name) < strings.ToLower(tenures[j].name)
+	})
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetTitle("Tenure")
+	t.AppendHeader(table.Row{"Name", "Tag", "In Clan", "First Seen", "Last Seen", "Joins", "Tenure"})
+	for _, m := range tenures {
+		inClan := "no"
+		if m.present {
+			inClan = "yes"
+		}
+		tenure := getDuration(m.tenure)
+		if m.firstSeen.Equal(h.from) {
+			// The player was already in the clan when the first snapshot was taken
+			tenure = "at least " + tenure
+		}
+		t.AppendRow(table.Row{
+			m.name, m.tag, inClan, m.firstSeen.Local().Format(layout), m.lastSeen.Local().Format(layout), m.joins, tenure,
+		})
+	}
+
+	return e.Render() + "\n" + t.Render()
+}
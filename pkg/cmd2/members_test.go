@@ -0,0 +1,117 @@
+package cmd2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testClanMember returns a clan member with the given name, role and town hall level
+func testClanMember(tag string, name string, role string, townHall int) response.ClanMember {
+	return response.ClanMember{Tag: tag, Name: name, Role: role, TownHallLevel: townHall}
+}
+
+func TestGetMemberHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []archive.Members{
+		{Saved: day(1), Members: []response.ClanMember{
+			testClanMember("#A", "Alice", "leader", 15),
+			testClanMember("#B", "Bob", "member", 12),
+		}},
+		// #B is promoted and upgrades, #C joins
+		{Saved: day(2), Members: []response.ClanMember{
+			testClanMember("#A", "Alice", "leader", 15),
+			testClanMember("#B", "Bob", "admin", 13),
+			testClanMember("#C", "Carol", "member", 0),
+		}},
+		// #B leaves, #C changes name and gets a town hall level
+		{Saved: day(4), Members: []response.ClanMember{
+			testClanMember("#A", "Alice", "leader", 15),
+			testClanMember("#C", "Caz", "member", 11),
+		}},
+		// #B rejoins, #A is demoted
+		{Saved: day(5), Members: []response.ClanMember{
+			testClanMember("#A", "Alice", "coLeader", 15),
+			testClanMember("#B", "Bob", "member", 13),
+			testClanMember("#C", "Caz", "member", 11),
+		}},
+	}
+
+	h := getMemberHistory("#CLAN", snapshots, day(10))
+	if !h.from.Equal(day(1)) {
+		t.Errorf("from = %v, want %v", h.from, day(1))
+	}
+
+	wantEvents := []memberEvent{
+		{day(2), "#B", "Bob", eventPromotion, "Member to Elder"},
+		{day(2), "#B", "Bob", eventTownHall, "TH12 to TH13"},
+		{day(2), "#C", "Carol", eventJoin, "Joined as Member"},
+		{day(4), "#B", "Bob", eventLeave, "Left as Elder"},
+		{day(4), "#C", "Caz", eventNameChange, "Carol to Caz"},
+		{day(5), "#A", "Alice", eventDemotion, "Leader to Co-leader"},
+		{day(5), "#B", "Bob", eventRejoin, "Rejoined as Member"},
+	}
+	if len(h.events) != len(wantEvents) {
+		t.Fatalf("got %d events %v, want %d", len(h.events), h.events, len(wantEvents))
+	}
+	for i, want := range wantEvents {
+		if got := h.events[i]; got != want {
+			t.Errorf("event %d = %v, want %v", i, got, want)
+		}
+	}
+
+	tests := []struct {
+		tag       string
+		names     int
+		joins     int
+		firstSeen time.Time
+		present   bool
+		tenure    time.Duration
+	}{
+		{"#A", 1, 1, day(1), true, 9 * 24 * time.Hour},
+		// One day before leaving and five days since rejoining
+		{"#B", 1, 2, day(1), true, 3*24*time.Hour + 5*24*time.Hour},
+		{"#C", 2, 1, day(2), true, 8 * 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			m, ok := h.tenures[tt.tag]
+			if !ok {
+				t.Fatalf("no tenure for %s", tt.tag)
+			}
+			if len(m.names) != tt.names || m.joins != tt.joins || !m.firstSeen.Equal(tt.firstSeen) ||
+				m.present != tt.present || m.tenure != tt.tenure {
+				t.Errorf("tenure = %d names, %d joins, first seen %v, present %v, tenure %v, want %d, %d, %v, %v, %v",
+					len(m.names), m.joins, m.firstSeen, m.present, m.tenure,
+					tt.names, tt.joins, tt.firstSeen, tt.present, tt.tenure)
+			}
+		})
+	}
+
+	if h := getMemberHistory("#CLAN", nil, day(10)); len(h.events) != 0 || len(h.tenures) != 0 || !h.from.IsZero() {
+		t.Errorf("history without snapshots has %d events and %d tenures from %v", len(h.events), len(h.tenures), h.from)
+	}
+}
+
+func TestMemberTenureMatches(t *testing.T) {
+	m := memberTenure{tag: "#ABC", names: []string{"Carol", "Caz"}}
+	tests := []struct {
+		player string
+		want   bool
+	}{
+		{"#ABC", true},
+		{"abc", true},
+		{"carol", true},
+		{"Caz", true},
+		{"Bob", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.player, func(t *testing.T) {
+			if got := m.matches(tt.player); got != tt.want {
+				t.Errorf("matches(%q) = %v, want %v", tt.player, got, tt.want)
+			}
+		})
+	}
+}
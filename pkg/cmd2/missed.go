@@ -0,0 +1,310 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultStrikeLimit is the number of strikes at which a member is flagged
+	defaultStrikeLimit = 3
+
+	// Ways of counting strikes
+	strikePerWar    = "war"
+	strikePerAttack = "attack"
+)
+
+// strikePolicy is how strikes are given for missed attacks
+type strikePolicy struct {
+	per   string // A strike for each war with a missed attack, or for each missed attack
+	limit int    // Number of strikes at which a member is flagged, or 0 to never flag
+}
+
+// missedAttacker is a member who didn't use all their attacks in a war
+type missedAttacker struct {
+	name        string // Name of the member
+	mapPosition int    // Map position of the member
+	missed      int    // Number of attacks not used
+}
+
+// missedWar is a war that has ended, with the members who didn't use all their attacks
+type missedWar struct {
+	start        time.Time        // Time preparation day started
+	opponentName string           // Name of the opponent
+	members      []missedAttacker // Members with missed attacks, in map order
+}
+
+// missedMember is a member's missed attacks over a set of wars
+type missedMember struct {
+	name       string // Name of the member
+	tag        string // Tag of the member
+	wars       int    // Number of wars the member was in
+	available  int    // Number of attacks available to the member
+	missed     int    // Number of attacks not used
+	warsMissed int    // Number of wars with a missed attack
+	strikes    int    // Number of strikes under the policy
+}
+
+// missedReport is the missed attacks for a clan over a set of wars
+type missedReport struct {
+	clanName string         // Name of the clan
+	cwl      bool           // The wars are CWL wars or regular wars
+	policy   strikePolicy   // How strikes are given
+	wars     []missedWar    // Wars with missed attacks, oldest first
+	count    int            // Number of wars that have ended
+	members  []missedMember // Members with missed attacks, most strikes first
+}
+
+// getStrikePolicy gets the strike policy from the options or, if they are not present, from the
+// configuration or the defaults
+func getStrikePolicy(c *cli.Context) (strikePolicy, error) {
+	p := strikePolicy{per: strikePerWar, limit: defaultStrikeLimit}
+	if config.Data.Missed.StrikePer != "" {
+		p.per = strings.ToLower(config.Data.Missed.StrikePer)
+	}
+	if config.Data.Missed.StrikeLimit != nil {
+		p.limit = *config.Data.Missed.StrikeLimit
+	}
+	if c.IsSet("strike-per") {
+		p.per = strings.ToLower(c.String("strike-per"))
+	}
+	if c.IsSet("strikes") {
+		p.limit = c.Int("strikes")
+	}
+
+	if p.per != strikePerWar && p.per != strikePerAttack {
+		err := fmt.Errorf("invalid strike policy %q, expected %s or %s", p.per, strikePerWar, strikePerAttack)
+		fmt.Println(err)
+		return p, err
+	}
+	return p, nil
+}
+
+// getLiveWars gets the clan's current war or, for CWL, its wars in each round of the current league.
+// A private war log or a clan that isn't in the league isn't treated as a failure, as the archive may
+// still have the wars.
+func getLiveWars(tag string, cwl bool) ([]archive.War, error) {
+	var wars []archive.War
+
+	if !cwl {
+		req := request.ClanCurrentWar{Tag: tag}
+		war, err := req.Get()
+		if err != nil {
+			log.Info("no current war for ", tag, ": ", err)
+			return wars, nil
+		}
+		if war.State != "" && war.State != "notInWar" {
+			wars = append(wars, archive.War{ClanTag: tag, War: &war})
+		}
+		return wars, nil
+	}
+
+	wars, err := getLeagueWars(tag, nil)
+	if err != nil {
+		fmt.Println(err)
+	}
+	return wars, err
+}
+
+// getMissedReport finds the members who didn't use all their attacks in the wars that have ended
+func getMissedReport(wars []archive.War, policy strikePolicy) missedReport {
+	r := missedReport{policy: policy}
+	members := make(map[string]*missedMember)
+	for _, w := range wars {
+		if w.State() != "warEnded" {
+			continue
+		}
+		cw := w.ClanWar()
+		r.count++
+		r.clanName = cw.Clan.Name
+
+		mw := missedWar{start: w.PreparationStart(), opponentName: cw.Opponent.Name}
+		for _, m := range cw.Clan.Members {
+			mm, ok := members[m.Tag]
+			if !ok {
+				mm = &missedMember{tag: m.Tag}
+				members[m.Tag] = mm
+			}
+			mm.name = m.Name
+			mm.wars++
+			mm.available += w.AttacksPerMember()
+
+			missed := w.AttacksPerMember() - len(m.Attacks)
+			if missed <= 0 {
+				continue
+			}
+			mm.missed += missed
+			mm.warsMissed++
+			mw.members = append(mw.members, missedAttacker{name: m.Name, mapPosition: m.MapPosition, missed: missed})
+		}
+		if len(mw.members) > 0 {
+			sort.Slice(mw.members, func(i, j int) bool { return mw.members[i].mapPosition < mw.members[j].mapPosition })
+			r.wars = append(r.wars, mw)
+		}
+	}
+
+	for _, m := range members {
+		if m.missed == 0 {
+			continue
+		}
+		m.strikes = m.warsMissed
+		if policy.per == strikePerAttack {
+			m.strikes = m.missed
+		}
+		r.members = append(r.members, *m)
+	}
+	sort.Slice(r.members, func(i, j int) bool {
+		a, b := r.members[i], r.members[j]
+		if a.strikes != b.strikes {
+			return a.strikes > b.strikes
+		}
+		if a.missed != b.missed {
+			return a.missed > b.missed
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+
+	return r
+}
+
+// missedAttacks gets the missed attacks for the clan's regular wars or CWL wars, from the archive and
+// from the wars that are still available from the API
+func missedAttacks(c *cli.Context, cwl bool) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getDateRange(c)
+	if err != nil {
+		return err
+	}
+	policy, err := getStrikePolicy(c)
+	if err != nil {
+		return err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	archived, err := a.Wars(tag, from, to)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	live, err := getLiveWars(tag, cwl)
+	if err != nil {
+		return err
+	}
+
+	// The live wars are newer than any snapshot of the same war in the archive
+	byID := make(map[string]archive.War)
+	for _, w := range archived {
+		if w.IsLeagueWar() == cwl {
+			byID[w.ID()] = w
+		}
+	}
+	for _, w := range live {
+		start := w.PreparationStart()
+		if (!from.IsZero() && start.Before(from)) || (!to.IsZero() && !start.Before(to)) {
+			continue
+		}
+		byID[w.ID()] = w
+	}
+	var wars []archive.War
+	for _, w := range byID {
+		wars = append(wars, w)
+	}
+	sort.Slice(wars, func(i, j int) bool { return wars[i].PreparationStart().Before(wars[j].PreparationStart()) })
+
+	r := getMissedReport(wars, policy)
+	r.cwl = cwl
+
+	fmt.Println(r)
+
+	return nil
+}
+
+// WarMissed lists the members who didn't use their attacks in each regular war that has ended, and
+// their missed attacks and strikes over all the wars
+func WarMissed(c *cli.Context) error {
+	return missedAttacks(c, false)
+}
+
+// CwlMissed lists the members who didn't use their attack in each CWL war that has ended, and their
+// missed attacks and strikes over all the wars
+func CwlMissed(c *cli.Context) error {
+	return missedAttacks(c, true)
+}
+
+// String returns a string representation of the missed attacks for a clan
+func (r missedReport) String() string {
+	kind := "wars"
+	if r.cwl {
+		kind = "CWL wars"
+	}
+
+	wt := table.NewWriter()
+	wt.SetStyle(table.StyleColoredBright)
+	wt.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 4, Align: text.AlignRight},
+	})
+	wt.SetTitle("Missed attacks by war")
+	wt.AppendHeader(table.Row{"War", "Opponent", "Name", "Missed"})
+	for _, w := range r.wars {
+		for i, m := range w.members {
+			if i == 0 {
+				wt.AppendRow(table.Row{w.start.Local().Format("2006-01-02"), w.opponentName, m.name, m.missed})
+			} else {
+				wt.AppendRow(table.Row{"", "", m.name, m.missed})
+			}
+		}
+		wt.AppendSeparator()
+	}
+
+	mt := table.NewWriter()
+	mt.SetStyle(table.StyleColoredBright)
+	mt.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+	})
+	mt.SetTitle(fmt.Sprintf("%s missed attacks over %d %s", r.clanName, r.count, kind))
+	mt.AppendHeader(table.Row{"#", "Name", "Tag", "Wars", "Missed", "Strikes", "Flagged"})
+	for i, m := range r.members {
+		flagged := ""
+		if r.policy.limit > 0 && m.strikes >= r.policy.limit {
+			flagged = "yes"
+		}
+		missed := strconv.Itoa(m.missed) + "/" + strconv.Itoa(m.available)
+		mt.AppendRow(table.Row{i + 1, m.name, m.tag, m.wars, missed, m.strikes, flagged})
+	}
+	caption := "A strike for each " + r.policy.per + " with a missed attack"
+	if r.policy.per == strikePerAttack {
+		caption = "A strike for each missed attack"
+	}
+	if r.policy.limit > 0 {
+		caption += ", flagged at " + strconv.Itoa(r.policy.limit) + " strikes"
+	}
+	mt.SetCaption("%s", caption)
+
+	if len(r.wars) == 0 {
+		return fmt.Sprintf("No missed attacks in %d %s", r.count, kind)
+	}
+	return wt.Render() + "\n" + mt.Render()
+}
@@ -0,0 +1,92 @@
+package cmd2
+
+import (
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testWarMember returns a member of a war who made the given number of attacks
+func testWarMember(tag string, mapPosition int, attacks int) response.ClanWarMember {
+	m := response.ClanWarMember{Tag: tag, Name: tag, MapPosition: mapPosition}
+	for i := 0; i < attacks; i++ {
+		m.Attacks = append(m.Attacks, testAttack("#X", 2, 70))
+	}
+	return m
+}
+
+func TestGetMissedReport(t *testing.T) {
+	team := func(members ...response.ClanWarMember) response.ClanWarTeam {
+		return response.ClanWarTeam{Tag: "#CLAN", Name: "clan", Members: members}
+	}
+	wars := []archive.War{
+		{ClanTag: "#CLAN", War: &response.ClanWar{
+			State:                "warEnded",
+			PreparationStartTime: "20240101T000000.000Z",
+			Clan:                 team(testWarMember("#A", 1, 2), testWarMember("#B", 2, 0), testWarMember("#C", 3, 1)),
+			Opponent:             response.ClanWarTeam{Tag: "#OPP", Name: "opp"},
+		}},
+		{ClanTag: "#CLAN", War: &response.ClanWar{
+			State:                "warEnded",
+			PreparationStartTime: "20240103T000000.000Z",
+			Clan:                 team(testWarMember("#A", 1, 1), testWarMember("#C", 2, 1)),
+			// The clan is the opponent in the response
+			Opponent: response.ClanWarTeam{Tag: "#OPP2", Name: "opp2"},
+		}},
+		// A CWL war has one attack for each member
+		{ClanTag: "#CLAN", LeagueWar: &response.ClanWarLeagueWar{
+			State:                "warEnded",
+			PreparationStartTime: "20240105T000000.000Z",
+			Clan:                 response.ClanWarTeam{Tag: "#CWL", Name: "cwl"},
+			Opponent:             team(testWarMember("#A", 1, 1), testWarMember("#B", 2, 0)),
+		}},
+		// Wars that haven't ended aren't counted
+		{ClanTag: "#CLAN", War: &response.ClanWar{
+			State:                "inWar",
+			PreparationStartTime: "20240107T000000.000Z",
+			Clan:                 team(testWarMember("#A", 1, 0), testWarMember("#D", 2, 0)),
+		}},
+	}
+	wars[1].War.Clan, wars[1].War.Opponent = wars[1].War.Opponent, wars[1].War.Clan
+
+	tests := []struct {
+		per     string
+		members []missedMember
+	}{
+		{strikePerWar, []missedMember{
+			{tag: "#B", wars: 2, available: 3, missed: 3, warsMissed: 2, strikes: 2},
+			{tag: "#C", wars: 2, available: 4, missed: 2, warsMissed: 2, strikes: 2},
+			{tag: "#A", wars: 3, available: 5, missed: 1, warsMissed: 1, strikes: 1},
+		}},
+		{strikePerAttack, []missedMember{
+			{tag: "#B", wars: 2, available: 3, missed: 3, warsMissed: 2, strikes: 3},
+			{tag: "#C", wars: 2, available: 4, missed: 2, warsMissed: 2, strikes: 2},
+			{tag: "#A", wars: 3, available: 5, missed: 1, warsMissed: 1, strikes: 1},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.per, func(t *testing.T) {
+			r := getMissedReport(wars, strikePolicy{per: tt.per, limit: defaultStrikeLimit})
+			if r.count != 3 {
+				t.Errorf("counted %d wars, want 3", r.count)
+			}
+			if len(r.wars) != 3 {
+				t.Errorf("got %d wars with missed attacks, want 3", len(r.wars))
+			} else if r.wars[1].opponentName != "opp2" || len(r.wars[1].members) != 2 {
+				t.Errorf("second war against %s with %d members missing attacks, want opp2 with 2",
+					r.wars[1].opponentName, len(r.wars[1].members))
+			}
+			if len(r.members) != len(tt.members) {
+				t.Fatalf("got %d members with missed attacks, want %d", len(r.members), len(tt.members))
+			}
+			for i, want := range tt.members {
+				got := r.members[i]
+				got.name = ""
+				if got != want {
+					t.Errorf("member %d = %+v, want %+v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package cmd2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// playerProfile is the profile of a player
+type playerProfile struct {
+	name                string              // Name of the player
+	tag                 string              // Tag of the player
+	townHall            int                 // Town hall level
+	townHallWeapon      int                 // Town hall weapon level, for town halls that have one
+	expLevel            int                 // Experience level
+	clanName            string              // Name of the player's clan
+	role                string              // Role of the player in the clan
+	warPreference       string              // Whether the player is opted in to clan wars
+	warStars            int                 // Number of war stars earned
+	trophies            int                 // Home village trophies
+	league              string              // Home village league
+	builderHall         int                 // Builder hall level
+	builderBaseTrophies int                 // Builder base trophies
+	builderBaseLeague   string              // Builder base league
+	donations           int                 // Troops donated this season
+	donationsReceived   int                 // Troops received this season
+	capitalGold         int                 // Capital gold contributed to the clan capital
+	completion          response.Completion // How close the home village is to max for the town hall
+	units               []playerUnitSet     // Heroes, equipment, pets, troops and spells of the player
+}
+
+// playerUnitSet is a set of units of the same kind
+type playerUnitSet struct {
+	kind  string           // The kind of unit, such as heroes or pets
+	units []response.Troop // The units
+}
+
+// getPlayerProfile returns a playerProfile created from a player
+func getPlayerProfile(p response.Player) playerProfile {
+	return playerProfile{
+		name:                p.Name,
+		tag:                 p.Tag,
+		townHall:            p.TownHallLevel,
+		townHallWeapon:      p.TownHallWeaponLevel,
+		expLevel:            p.ExpLevel,
+		clanName:            p.Clan.Name,
+		role:                p.Role,
+		warPreference:       p.WarPreference,
+		warStars:            p.WarStars,
+		trophies:            p.Trophies,
+		league:              p.League.Name,
+		builderHall:         p.BuilderHallLevel,
+		builderBaseTrophies: p.BuilderBaseTrophies,
+		builderBaseLeague:   p.BuilderBaseLeague.Name,
+		donations:           p.Donations,
+		donationsReceived:   p.DonationsReceived,
+		capitalGold:         p.ClanCapitalContributions,
+		completion:          p.Completion(),
+		units:               append(getHomeUnitSets(p), playerUnitSet{kind: "Builder Base", units: p.BuilderBaseTroops()}),
+	}
+}
+
+// getHomeUnitSets returns the player's heroes, equipment, pets, siege machines, troops and spells in the
+// home village.  Super troops are left out, as they share the level of the troop they boost.
+func getHomeUnitSets(p response.Player) []playerUnitSet {
+	return []playerUnitSet{
+		{kind: "Heroes", units: p.HomeHeroes()},
+		{kind: "Equipment", units: p.HeroEquipment},
+		{kind: "Pets", units: p.Pets()},
+		{kind: "Siege Machines", units: p.SiegeMachines()},
+		{kind: "Troops", units: p.HomeTroops()},
+		{kind: "Spells", units: p.HomeSpells()},
+	}
+}
+
+// PlayerGet gets the profile of a player
+func PlayerGet(c *cli.Context) error {
+	tag := response.NormalizeTag(c.String("player"))
+	if tag == "" {
+		cli.ShowCommandHelpAndExit(c, "get", -1)
+	}
+
+	req := request.Player{Tag: tag}
+	p, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	fmt.Println(getPlayerProfile(p))
+
+	return nil
+}
+
+// PlayerVerify verifies that an API token belongs to a player
+func PlayerVerify(c *cli.Context) error {
+	tag := response.NormalizeTag(c.String("player"))
+	token := c.String("token")
+	if tag == "" || token == "" {
+		cli.ShowCommandHelpAndExit(c, "verify", -1)
+	}
+
+	req := request.VerifyPlayerToken{Tag: tag, Token: token}
+	vt, err := req.Verify()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	if !vt.Valid() {
+		err := fmt.Errorf("token is not valid for player %s, status=%s", vt.Tag, vt.Status)
+		fmt.Println(err)
+		return err
+	}
+
+	fmt.Printf("Token is valid for player %s\n", vt.Tag)
+	return nil
+}
+
+// getPercent returns a string representation of a completion percentage, or a question mark if the max
+// level tables don't cover the player's town hall
+func getPercent(percent float64, known bool) string {
+	if !known {
+		return "?"
+	}
+	return fmt.Sprintf("%.0f%%", percent)
+}
+
+// getCompletionNote returns a note explaining the question marks shown for completion percentages
+func getCompletionNote() string {
+	return "? means completion isn't known, as the max level tables (" + response.MaxLevelsVersion +
+		" update) only cover town halls up to " + strconv.Itoa(response.MaxLevelsTownHall)
+}
+
+// String returns a string representation of a player's profile
+func (p playerProfile) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetTitle(p.name + " (" + p.tag + ")")
+
+	townHall := strconv.Itoa(p.townHall)
+	if p.townHallWeapon > 0 {
+		townHall += " (weapon " + strconv.Itoa(p.townHallWeapon) + ")"
+	}
+	league := p.league
+	if league == "" {
+		league = "Unranked"
+	}
+	clan := p.clanName
+	if clan != "" && p.role != "" {
+		clan += " (" + p.role + ")"
+	}
+
+	t.AppendRow(table.Row{"Town Hall", townHall})
+	t.AppendRow(table.Row{"Experience", p.expLevel})
+	t.AppendRow(table.Row{"Clan", clan})
+	t.AppendRow(table.Row{"War Preference", p.warPreference})
+	t.AppendRow(table.Row{"War Stars", p.warStars})
+	t.AppendRow(table.Row{"Trophies", strconv.Itoa(p.trophies) + " (" + league + ")"})
+	if p.builderHall > 0 {
+		t.AppendRow(table.Row{"Builder Hall", p.builderHall})
+		t.AppendRow(table.Row{"Builder Trophies", strconv.Itoa(p.builderBaseTrophies) + " (" + p.builderBaseLeague + ")"})
+	}
+	t.AppendRow(table.Row{"Donations", strconv.Itoa(p.donations) + " / " + strconv.Itoa(p.donationsReceived)})
+	t.AppendRow(table.Row{"Capital Gold", p.capitalGold})
+	t.AppendRow(table.Row{"Rushed", getPercent(p.completion.Rushed, p.completion.Known)})
+	t.AppendRow(table.Row{"Offense", getPercent(p.completion.Offense, p.completion.Known)})
+	t.AppendRow(table.Row{"Heroes", getPercent(p.completion.Heroes, p.completion.Known)})
+	if !p.completion.Known {
+		t.SetCaption("%s", getCompletionNote())
+	}
+
+	u := table.NewWriter()
+	u.SetStyle(table.StyleColoredBright)
+	u.SetTitle("Max levels for TH" + strconv.Itoa(p.townHall) + " as of the " + response.MaxLevelsVersion + " update")
+	u.AppendHeader(table.Row{"Kind", "Levels"})
+	for _, set := range p.units {
+		if len(set.units) == 0 {
+			continue
+		}
+		var levels []string
+		for _, unit := range set.units {
+			levels = append(levels, unit.Name+" "+strconv.Itoa(unit.Level)+"/"+strconv.Itoa(response.MaxLevel(unit, p.townHall)))
+		}
+		u.AppendRow(table.Row{set.kind, strings.Join(levels, "\n")})
+	}
+
+	return t.Render() + "\n" + u.Render()
+}
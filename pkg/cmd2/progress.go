@@ -0,0 +1,349 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// defaultUpgradeDays is the number of days covered by the clan's upgrade summary
+	defaultUpgradeDays = 7
+)
+
+// unitUpgrade is an upgrade of a player's town hall, hero, equipment, pet, troop or spell found
+// between two snapshots of the player
+type unitUpgrade struct {
+	time time.Time // Time of the snapshot in which the upgrade was first seen
+	kind string    // The kind of unit, such as heroes or pets
+	name string    // Name of the unit
+	from int       // Level before the upgrade, or zero if the unit was unlocked
+	to   int       // Level after the upgrade
+}
+
+// unitCompletion is how close a set of a player's units are to their max levels
+type unitCompletion struct {
+	kind   string // The kind of unit, such as heroes or pets
+	levels int    // Total levels of the units
+	max    int    // Total max levels of the units
+}
+
+// playerProgress is the upgrade timeline of a player
+type playerProgress struct {
+	name       string           // Name of the player
+	tag        string           // Tag of the player
+	townHall   int              // Town hall level in the latest snapshot
+	from       time.Time        // Time of the first snapshot
+	to         time.Time        // Time of the latest snapshot
+	upgrades   []unitUpgrade    // Upgrades, oldest first
+	completion []unitCompletion // Completion of each kind of unit in the latest snapshot
+}
+
+// memberUpgrades are the upgrades made by a member over a period
+type memberUpgrades struct {
+	name     string        // Name of the member
+	tag      string        // Tag of the member
+	townHall int           // Town hall level in the latest snapshot
+	upgrades []unitUpgrade // Upgrades in the period
+}
+
+// clanUpgrades are the upgrades made by a clan's members over a period
+type clanUpgrades struct {
+	clanTag string           // Tag of the clan
+	since   time.Time        // Start of the period
+	members []memberUpgrades // Upgrades of each member, most levels gained first
+}
+
+// levels returns the number of levels gained by the upgrade
+func (u unitUpgrade) levels() int {
+	return u.to - u.from
+}
+
+// String returns a string representation of the upgrade
+func (u unitUpgrade) String() string {
+	if u.from == 0 {
+		return u.name + " unlocked at " + strconv.Itoa(u.to)
+	}
+	return u.name + " " + strconv.Itoa(u.from) + " → " + strconv.Itoa(u.to)
+}
+
+// percent returns the levels of the units as a percentage of their max levels
+func (c unitCompletion) percent() float64 {
+	if c.max == 0 {
+		return 0
+	}
+	return float64(c.levels) * 100 / float64(c.max)
+}
+
+// getUpgrades returns the upgrades found between two snapshots of a player
+func getUpgrades(prev response.Player, curr response.Player, t time.Time) []unitUpgrade {
+	var upgrades []unitUpgrade
+	if prev.TownHallLevel > 0 && curr.TownHallLevel > prev.TownHallLevel {
+		upgrades = append(upgrades, unitUpgrade{t, "Town Hall", "Town Hall", prev.TownHallLevel, curr.TownHallLevel})
+	}
+
+	before := make(map[string]int)
+	for _, set := range getHomeUnitSets(prev) {
+		for _, u := range set.units {
+			before[set.kind+"/"+u.Name] = u.Level
+		}
+	}
+	for _, set := range getHomeUnitSets(curr) {
+		for _, u := range set.units {
+			if level := before[set.kind+"/"+u.Name]; u.Level > level {
+				upgrades = append(upgrades, unitUpgrade{t, set.kind, u.Name, level, u.Level})
+			}
+		}
+	}
+	return upgrades
+}
+
+// getUnitCompletion returns how close each kind of the player's units are to their max levels for the
+// player's town hall
+func getUnitCompletion(p response.Player) []unitCompletion {
+	var completion []unitCompletion
+	for _, set := range getHomeUnitSets(p) {
+		if len(set.units) == 0 {
+			continue
+		}
+		c := unitCompletion{kind: set.kind}
+		for _, u := range set.units {
+			c.levels += u.Level
+			c.max += response.MaxLevel(u, p.TownHallLevel)
+		}
+		completion = append(completion, c)
+	}
+	return completion
+}
+
+// getPlayerProgress returns the upgrade timeline of a player from its snapshots, which must be oldest first
+func getPlayerProgress(snapshots []archive.Player) playerProgress {
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	pp := playerProgress{
+		name:       last.Player.Name,
+		tag:        last.Player.Tag,
+		townHall:   last.Player.TownHallLevel,
+		from:       first.Saved,
+		to:         last.Saved,
+		completion: getUnitCompletion(last.Player),
+	}
+	for i := 1; i < len(snapshots); i++ {
+		pp.upgrades = append(pp.upgrades, getUpgrades(snapshots[i-1].Player, snapshots[i].Player, snapshots[i].Saved)...)
+	}
+	return pp
+}
+
+// weekStart returns the start of the week, on Monday, containing the given time
+func weekStart(t time.Time) time.Time {
+	t = t.Local()
+	days := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-days, 0, 0, 0, 0, time.Local)
+}
+
+// Progress gets the upgrade timeline of the player given by the `player` option or, if that is not
+// present, the upgrades made by each member of the clan over the last week, from the snapshots in the archive
+func Progress(c *cli.Context) error {
+	var since time.Time
+	if s := c.String("since"); s != "" {
+		var err error
+		since, err = time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			err = fmt.Errorf("invalid since date %q, expected YYYY-MM-DD", s)
+			fmt.Println(err)
+			return err
+		}
+	}
+
+	player := c.String("player")
+	if player != "" {
+		return playerProgressReport(c, player, since)
+	}
+
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+	if since.IsZero() {
+		since = time.Now().AddDate(0, 0, -defaultUpgradeDays)
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	// The latest member snapshot gives the members to report on
+	members, err := a.Members(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	if len(members) == 0 {
+		err := fmt.Errorf("no member snapshots saved for %s, run the daemon to save them", tag)
+		fmt.Println(err)
+		return err
+	}
+
+	cu := clanUpgrades{clanTag: tag, since: since}
+	for _, m := range members[len(members)-1].Members {
+		snapshots, err := a.Players(m.Tag, time.Time{}, time.Time{})
+		if err != nil {
+			log.Error("failed to read the archive")
+			fmt.Println(err)
+			return err
+		}
+		if len(snapshots) == 0 {
+			continue
+		}
+
+		// Compare against the last snapshot before the period, or the first one in it
+		base := 0
+		for i, s := range snapshots {
+			if s.Saved.After(since) {
+				break
+			}
+			base = i
+		}
+		latest := snapshots[len(snapshots)-1]
+		mu := memberUpgrades{name: latest.Player.Name, tag: m.Tag, townHall: latest.Player.TownHallLevel}
+		for i := base + 1; i < len(snapshots); i++ {
+			mu.upgrades = append(mu.upgrades, getUpgrades(snapshots[i-1].Player, snapshots[i].Player, snapshots[i].Saved)...)
+		}
+		if len(mu.upgrades) > 0 {
+			cu.members = append(cu.members, mu)
+		}
+	}
+
+	sort.Slice(cu.members, func(i, j int) bool {
+		a, b := cu.members[i].levels(), cu.members[j].levels()
+		if a != b {
+			return a > b
+		}
+		return strings.ToLower(cu.members[i].name) < strings.ToLower(cu.members[j].name)
+	})
+
+	fmt.Println(cu)
+
+	return nil
+}
+
+// playerProgressReport prints the upgrade timeline of a player since the given time
+func playerProgressReport(c *cli.Context, tag string, since time.Time) error {
+	tag = response.NormalizeTag(tag)
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	snapshots, err := a.Players(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	if len(snapshots) == 0 {
+		err := fmt.Errorf("no snapshots saved for player %s, run the daemon to save them", tag)
+		fmt.Println(err)
+		return err
+	}
+
+	pp := getPlayerProgress(snapshots)
+	var upgrades []unitUpgrade
+	for _, u := range pp.upgrades {
+		if !u.time.Before(since) {
+			upgrades = append(upgrades, u)
+		}
+	}
+	pp.upgrades = upgrades
+
+	fmt.Println(pp)
+
+	return nil
+}
+
+// levels returns the number of levels gained by the member
+func (m memberUpgrades) levels() int {
+	n := 0
+	for _, u := range m.upgrades {
+		n += u.levels()
+	}
+	return n
+}
+
+// String returns a string representation of a player's upgrade timeline
+func (pp playerProgress) String() string {
+	const layout = "2006-01-02 15:04"
+
+	u := table.NewWriter()
+	u.SetStyle(table.StyleColoredBright)
+	u.SetTitle(fmt.Sprintf("%s (%s) upgrades from %s to %s", pp.name, pp.tag, pp.from.Local().Format("2006-01-02"), pp.to.Local().Format("2006-01-02")))
+	u.AppendHeader(table.Row{"Time", "Kind", "Upgrade"})
+	for _, up := range pp.upgrades {
+		u.AppendRow(table.Row{up.time.Local().Format(layout), up.kind, up.String()})
+	}
+
+	// Levels gained in each week, most recent first
+	weeks := make(map[time.Time]int)
+	var starts []time.Time
+	for _, up := range pp.upgrades {
+		w := weekStart(up.time)
+		if _, ok := weeks[w]; !ok {
+			starts = append(starts, w)
+		}
+		weeks[w] += up.levels()
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i].After(starts[j]) })
+
+	w := table.NewWriter()
+	w.SetStyle(table.StyleColoredBright)
+	w.SetColumnConfigs([]table.ColumnConfig{{Number: 2, Align: text.AlignRight}})
+	w.SetTitle("Levels per week")
+	w.AppendHeader(table.Row{"Week of", "Levels"})
+	for _, s := range starts {
+		w.AppendRow(table.Row{s.Format("2006-01-02"), weeks[s]})
+	}
+
+	c := table.NewWriter()
+	c.SetStyle(table.StyleColoredBright)
+	c.SetColumnConfigs([]table.ColumnConfig{{Number: 2, Align: text.AlignRight}, {Number: 3, Align: text.AlignRight}})
+	c.SetTitle("Completion at TH" + strconv.Itoa(pp.townHall))
+	c.AppendHeader(table.Row{"Kind", "Levels", "To Max"})
+	for _, uc := range pp.completion {
+		c.AppendRow(table.Row{uc.kind, strconv.Itoa(uc.levels) + "/" + strconv.Itoa(uc.max), fmt.Sprintf("%.0f%%", uc.percent())})
+	}
+
+	return u.Render() + "\n" + w.Render() + "\n" + c.Render()
+}
+
+// String returns a string representation of the upgrades made by a clan's members
+func (cu clanUpgrades) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{{Number: 3, Align: text.AlignRight}})
+
+	t.SetTitle(cu.clanTag + " upgrades since " + cu.since.Local().Format("2006-01-02"))
+	t.AppendHeader(table.Row{"Name", "TH", "Levels", "Upgrades"})
+	for _, m := range cu.members {
+		var upgrades []string
+		for _, u := range m.upgrades {
+			upgrades = append(upgrades, u.String())
+		}
+		t.AppendRow(table.Row{m.name, m.townHall, m.levels(), strings.Join(upgrades, "\n")})
+	}
+
+	return t.Render()
+}
@@ -0,0 +1,115 @@
+package cmd2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testUnit returns a unit with the given level in the given village
+func testUnit(name string, level int, village string) response.Troop {
+	return response.Troop{Name: name, Level: level, MaxLevel: level, Village: village}
+}
+
+// testPlayer returns a player with the given town hall level, heroes and troops
+func testPlayer(townHall int, heroes []response.Troop, troops []response.Troop) response.Player {
+	return response.Player{Tag: "#P", Name: "Player", TownHallLevel: townHall, Heroes: heroes, Troops: troops}
+}
+
+func TestGetUpgrades(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	home, builder := response.HomeVillage, response.BuilderBase
+
+	tests := []struct {
+		name string
+		prev response.Player
+		curr response.Player
+		want []unitUpgrade
+	}{
+		{
+			name: "no changes",
+			prev: testPlayer(13, []response.Troop{testUnit("Barbarian King", 70, home)}, nil),
+			curr: testPlayer(13, []response.Troop{testUnit("Barbarian King", 70, home)}, nil),
+		},
+		{
+			name: "town hall and hero",
+			prev: testPlayer(12, []response.Troop{testUnit("Barbarian King", 65, home)}, nil),
+			curr: testPlayer(13, []response.Troop{testUnit("Barbarian King", 67, home)}, nil),
+			want: []unitUpgrade{
+				{now, "Town Hall", "Town Hall", 12, 13},
+				{now, "Heroes", "Barbarian King", 65, 67},
+			},
+		},
+		{
+			name: "town hall missing from the older snapshot",
+			prev: testPlayer(0, nil, nil),
+			curr: testPlayer(13, nil, nil),
+		},
+		{
+			name: "unlocked pet and troop",
+			prev: testPlayer(14, nil, []response.Troop{testUnit("Barbarian", 10, home)}),
+			curr: testPlayer(14, nil, []response.Troop{testUnit("Barbarian", 10, home), testUnit("L.A.S.S.I", 1, home), testUnit("Yeti", 2, home)}),
+			want: []unitUpgrade{
+				{now, "Pets", "L.A.S.S.I", 0, 1},
+				{now, "Troops", "Yeti", 0, 2},
+			},
+		},
+		{
+			name: "builder base is ignored",
+			prev: testPlayer(14, nil, []response.Troop{testUnit("Raged Barbarian", 10, builder)}),
+			curr: testPlayer(14, nil, []response.Troop{testUnit("Raged Barbarian", 12, builder)}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getUpgrades(tt.prev, tt.curr, now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("getUpgrades = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("upgrade %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetPlayerProgress(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	home := response.HomeVillage
+	snapshots := []archive.Player{
+		{Saved: day(1), Player: testPlayer(12, []response.Troop{testUnit("Barbarian King", 64, home)}, nil)},
+		{Saved: day(3), Player: testPlayer(12, []response.Troop{testUnit("Barbarian King", 65, home)}, nil)},
+		{Saved: day(8), Player: testPlayer(13, []response.Troop{testUnit("Barbarian King", 70, home)}, nil)},
+	}
+
+	pp := getPlayerProgress(snapshots)
+	if pp.townHall != 13 || !pp.from.Equal(day(1)) || !pp.to.Equal(day(8)) {
+		t.Errorf("progress TH%d from %v to %v, want TH13 from %v to %v", pp.townHall, pp.from, pp.to, day(1), day(8))
+	}
+
+	want := []unitUpgrade{
+		{day(3), "Heroes", "Barbarian King", 64, 65},
+		{day(8), "Town Hall", "Town Hall", 12, 13},
+		{day(8), "Heroes", "Barbarian King", 65, 70},
+	}
+	if len(pp.upgrades) != len(want) {
+		t.Fatalf("upgrades = %v, want %v", pp.upgrades, want)
+	}
+	for i := range want {
+		if pp.upgrades[i] != want[i] {
+			t.Errorf("upgrade %d = %v, want %v", i, pp.upgrades[i], want[i])
+		}
+	}
+
+	// Completion is for the latest snapshot, against the TH13 max levels
+	if len(pp.completion) != 1 {
+		t.Fatalf("completion = %v, want only heroes", pp.completion)
+	}
+	if c := pp.completion[0]; c.kind != "Heroes" || c.levels != 70 || c.max != 75 {
+		t.Errorf("completion = %s %d/%d, want Heroes 70/75", c.kind, c.levels, c.max)
+	}
+}
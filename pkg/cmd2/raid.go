@@ -0,0 +1,197 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// raidAttackLimit is the number of raid attacks a member has before earning a bonus attack
+	raidAttackLimit = 5
+)
+
+// raidSeasons is a list of raid weekends for a clan
+type raidSeasons struct {
+	seasons []raidSeason // The raid weekends, most recent first
+}
+
+// raidSeason is a summary of one raid weekend for a clan
+type raidSeason struct {
+	start              time.Time          // The time the raid weekend started
+	end                time.Time          // The time the raid weekend ends, or ended
+	state              string             // State of the raid weekend
+	loot               int                // Capital gold looted by the clan
+	raidsCompleted     int                // Number of enemy capitals fully destroyed
+	attacks            int                // Number of attacks made by the clan
+	districtsDestroyed int                // Number of enemy districts destroyed
+	offensiveReward    int                // Raid medals earned from attacking
+	defensiveReward    int                // Raid medals earned from defending
+	members            []raidSeasonMember // Members of the clan in the raid weekend
+}
+
+// raidSeasonMember is a member's attacks in a raid weekend
+type raidSeasonMember struct {
+	name        string // Name of the member
+	tag         string // Tag of the member
+	attacks     int    // Number of attacks made
+	attackLimit int    // Number of attacks available, including bonus attacks
+	looted      int    // Capital gold looted by the member
+}
+
+// getRaidSeason returns a raidSeason created from a capital raid season
+func getRaidSeason(s response.ClanCapitalRaidSeason) raidSeason {
+	rs := raidSeason{
+		start:              s.Start(),
+		end:                s.End(),
+		state:              s.State,
+		loot:               s.CapitalTotalLoot,
+		raidsCompleted:     s.RaidsCompleted,
+		attacks:            s.TotalAttacks,
+		districtsDestroyed: s.EnemyDistrictsDestroyed,
+		offensiveReward:    s.OffensiveReward,
+		defensiveReward:    s.DefensiveReward,
+	}
+	for _, m := range s.Members {
+		rs.members = append(rs.members, raidSeasonMember{
+			name:        m.Name,
+			tag:         m.Tag,
+			attacks:     m.Attacks,
+			attackLimit: m.TotalAttackLimit(),
+			looted:      m.CapitalResourcesLooted,
+		})
+	}
+	return rs
+}
+
+// RaidList gets the list of raid weekends for a clan
+func RaidList(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	req := request.ClanCapitalRaidSeasons{Tag: tag, Limit: c.Int("limit")}
+	seasons, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	var rs raidSeasons
+	for _, s := range seasons {
+		rs.seasons = append(rs.seasons, getRaidSeason(s))
+	}
+	fmt.Println(rs)
+
+	return nil
+}
+
+// RaidGet gets the members' attacks and capital gold for a raid weekend
+func RaidGet(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	// The raid weekend is counted back from the most recent one
+	n := c.Int("season")
+	req := request.ClanCapitalRaidSeasons{Tag: tag, Limit: n + 1}
+	seasons, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+	if n < 0 || n >= len(seasons) {
+		err := fmt.Errorf("raid weekend %d was not found, %d raid weekends are available", n, len(seasons))
+		fmt.Println(err)
+		return err
+	}
+	rs := getRaidSeason(seasons[n])
+
+	// Members of the clan who didn't raid at all don't appear in the raid season, so add them
+	// for the ongoing raid weekend
+	if rs.state == "ongoing" {
+		mreq := request.ClanMembers{Tag: tag}
+		members, err := mreq.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		raided := make(map[string]bool)
+		for _, m := range rs.members {
+			raided[m.tag] = true
+		}
+		for _, m := range members {
+			if !raided[m.Tag] {
+				rs.members = append(rs.members, raidSeasonMember{name: m.Name, tag: m.Tag, attackLimit: raidAttackLimit})
+			}
+		}
+	}
+
+	// Sort by the gold looted, then by name
+	sort.Slice(rs.members, func(i, j int) bool {
+		if rs.members[i].looted != rs.members[j].looted {
+			return rs.members[i].looted > rs.members[j].looted
+		}
+		return strings.ToLower(rs.members[i].name) < strings.ToLower(rs.members[j].name)
+	})
+
+	fmt.Println(rs)
+
+	return nil
+}
+
+// String returns a string representation of a list of raid weekends
+func (rs raidSeasons) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+
+	t.AppendHeader(table.Row{"Start", "State", "Gold", "Raids", "Attacks", "Districts", "Offense", "Defense"})
+	for _, s := range rs.seasons {
+		t.AppendRow(table.Row{s.start.Format("2006-01-02"), s.state, s.loot, s.raidsCompleted, s.attacks, s.districtsDestroyed, s.offensiveReward, s.defensiveReward})
+	}
+
+	return t.Render()
+}
+
+// String returns a string representation of the members' attacks in a raid weekend
+func (rs raidSeason) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 3, Align: text.AlignRight, AlignHeader: text.AlignCenter},
+		{Number: 4, Align: text.AlignRight},
+	})
+
+	t.SetTitle("Raid weekend " + rs.start.Format("2006-01-02") + " (" + rs.state + ")")
+	t.AppendHeader(table.Row{"#", "Name", "Attacks", "Gold", "Status"})
+	var missed int
+	for i, m := range rs.members {
+		var status string
+		if m.attacks >= m.attackLimit {
+			status = "all used"
+		} else {
+			status = "missed " + strconv.Itoa(m.attackLimit-m.attacks)
+			missed++
+		}
+		t.AppendRow(table.Row{i + 1, m.name, strconv.Itoa(m.attacks) + "/" + strconv.Itoa(m.attackLimit), m.looted, status})
+	}
+	t.AppendFooter(table.Row{"", "Total", rs.attacks, rs.loot, strconv.Itoa(missed) + " missed"})
+
+	return t.Render()
+}
@@ -0,0 +1,38 @@
+package cmd2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// Raw prints the unparsed response for any API path
+func Raw(c *cli.Context) error {
+	path := c.Args().First()
+	if path == "" {
+		cli.ShowCommandHelpAndExit(c, "raw", -1)
+	}
+
+	req := request.Raw{Path: path}
+	body, err := req.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	// Indent the response so it is readable, falling back to the response as received
+	var out bytes.Buffer
+	if err := json.Indent(&out, body, "", "  "); err != nil {
+		log.Debug("failed to indent the json response")
+		fmt.Println(string(body))
+		return nil
+	}
+	fmt.Println(out.String())
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// scoutWarLogSize is the number of recent wars from the opponent's war log shown in a scouting report
+	scoutWarLogSize = 10
+)
+
+// scoutPlayer is a member of the opponent in a war
+type scoutPlayer struct {
+	mapPosition int                 // Map position of the member
+	name        string              // Name of the member
+	townHall    int                 // Town hall level
+	heroes      heroes              // Hero levels
+	completion  response.Completion // Rush and completion scores
+	warStars    int                 // Stars earned in regular and CWL wars
+	cwlStars    int                 // Stars earned in CWL wars
+}
+
+// scoutTownHall is the opponent's members at one town hall level
+type scoutTownHall struct {
+	townHall int     // Town hall level
+	count    int     // Number of members
+	heroes   int     // Total hero levels
+	rushed   float64 // Total rush percentage of the members with a known rush score
+	known    int     // Number of members with a known rush score
+}
+
+// scoutLeagueSeason is a Clan War League season in the opponent's war log
+type scoutLeagueSeason struct {
+	end         time.Time // Time the last war of the season ended
+	stars       int       // Stars earned over the season
+	destruction float32   // Destruction percentage over the season
+}
+
+// scoutReport is a summary of the opponent in a war
+type scoutReport struct {
+	clanName     string              // Name of the clan
+	opponent     response.Clan       // The opponent's clan profile
+	warLogPublic bool                // The opponent's war log can be read
+	recent       []string            // Results of the opponent's recent wars, newest first
+	seasons      []scoutLeagueSeason // Clan War League seasons in the opponent's recent war log, newest first
+	townHalls    []scoutTownHall     // Members at each town hall level, highest first
+	players      []scoutPlayer       // Members of the opponent in the war, in map order
+}
+
+// WarScout gets a scouting report on the opponent in the current war, with their war record, recent
+// wars, town hall distribution and each member's heroes, rush score and war stars
+func WarScout(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	war, err := GetCurrentWar(c, tag)
+	if err != nil {
+		return err
+	}
+	if war.State == "notInWar" || war.State == "" {
+		err := fmt.Errorf("clan %s is not in a war", tag)
+		fmt.Println(err)
+		return err
+	}
+	if war.Clan.Tag != tag {
+		war.Clan, war.Opponent = war.Opponent, war.Clan
+	}
+
+	creq := request.Clan{Tag: war.Opponent.Tag}
+	opponent, err := creq.Get()
+	if err != nil {
+		log.Error("failed to get the response")
+		fmt.Println(err)
+		return err
+	}
+
+	r := scoutReport{clanName: war.Clan.Name, opponent: opponent, warLogPublic: opponent.IsWarLogPublic}
+
+	// The war log can only be read if the opponent has made it public
+	if opponent.IsWarLogPublic {
+		wreq := request.ClanWars{Tag: opponent.Tag, Limit: scoutWarLogSize}
+		wars, err := wreq.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		for _, w := range wars {
+			// Each CWL season has one entry in the war log, which has no opponent
+			if w.Opponent.Name == "" {
+				r.seasons = append(r.seasons, scoutLeagueSeason{
+					end:         getTime(w.EndTime),
+					stars:       w.Clan.Stars,
+					destruction: w.Clan.DestructionPercentage,
+				})
+				continue
+			}
+			r.recent = append(r.recent, w.Result)
+		}
+	}
+
+	members := append([]response.ClanWarMember{}, war.Opponent.Members...)
+	sort.Slice(members, func(i, j int) bool { return members[i].MapPosition < members[j].MapPosition })
+	townHalls := make(map[int]*scoutTownHall)
+	for i, m := range members {
+		req := request.Player{Tag: m.Tag}
+		p, err := req.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+
+		sp := scoutPlayer{
+			mapPosition: i + 1,
+			name:        m.Name,
+			townHall:    p.TownHallLevel,
+			heroes:      getHeroes(p.Heroes),
+			completion:  p.Completion(),
+			warStars:    p.WarStars,
+		}
+		sp.cwlStars, _ = p.Achievement("War League Legend")
+		r.players = append(r.players, sp)
+
+		th, ok := townHalls[sp.townHall]
+		if !ok {
+			th = &scoutTownHall{townHall: sp.townHall}
+			townHalls[sp.townHall] = th
+		}
+		th.count++
+		th.heroes += sp.heroes.total()
+		if sp.completion.Known {
+			th.rushed += sp.completion.Rushed
+			th.known++
+		}
+	}
+	for _, th := range townHalls {
+		r.townHalls = append(r.townHalls, *th)
+	}
+	sort.Slice(r.townHalls, func(i, j int) bool { return r.townHalls[i].townHall > r.townHalls[j].townHall })
+
+	fmt.Println(r)
+
+	return nil
+}
+
+// String returns a string representation of a scouting report
+func (r scoutReport) String() string {
+	o := r.opponent
+
+	ct := table.NewWriter()
+	ct.SetStyle(table.StyleColoredBright)
+	ct.SetTitle(r.clanName + " vs " + o.Name + " (" + o.Tag + ")")
+	league := o.WarLeague.Name
+	if league == "" {
+		league = "Unranked"
+	}
+	ct.AppendRow(table.Row{"Clan level", o.ClanLevel})
+	ct.AppendRow(table.Row{"War league", league})
+	ct.AppendRow(table.Row{"War frequency", o.WarFrequency})
+	if r.warLogPublic {
+		record := strconv.Itoa(o.WarWins) + "-" + strconv.Itoa(o.WarLosses) + "-" + strconv.Itoa(o.WarTies)
+		ct.AppendRow(table.Row{"Record (W-L-T)", record})
+		ct.AppendRow(table.Row{"Win streak", o.WarWinStreak})
+		var form []string
+		for _, res := range r.recent {
+			if res == "" {
+				form = append(form, "-")
+			} else {
+				form = append(form, strings.ToUpper(res[:1]))
+			}
+		}
+		ct.AppendRow(table.Row{"Recent wars", strings.Join(form, " ")})
+
+		// The API only has the clan's current war league, so earlier seasons show the stars earned
+		var seasons []string
+		for _, s := range r.seasons {
+			seasons = append(seasons, fmt.Sprintf("%s %d%s %.0f%%", s.end.Local().Format("2006-01"), s.stars, star, s.destruction))
+		}
+		if len(seasons) == 0 {
+			seasons = append(seasons, "none in recent wars")
+		}
+		ct.AppendRow(table.Row{"CWL seasons", strings.Join(seasons, ", ")})
+		ct.SetCaption("Earlier war leagues aren't available from the API")
+	} else {
+		ct.AppendRow(table.Row{"Record (W-L-T)", strconv.Itoa(o.WarWins) + " wins, war log is private"})
+	}
+
+	tt := table.NewWriter()
+	tt.SetStyle(table.StyleColoredBright)
+	tt.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Align: text.AlignRight},
+		{Number: 3, Align: text.AlignRight, AlignFooter: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+	})
+	tt.SetTitle("Town halls")
+	tt.AppendHeader(table.Row{"TH", "Count", "Avg Heroes", "Avg Rushed"})
+	totalHeroes := 0
+	for _, th := range r.townHalls {
+		rushed := "?"
+		if th.known > 0 {
+			rushed = fmt.Sprintf("%.0f%%", th.rushed/float64(th.known))
+		}
+		tt.AppendRow(table.Row{th.townHall, th.count, fmt.Sprintf("%.0f", float64(th.heroes)/float64(th.count)), rushed})
+		totalHeroes += th.heroes
+	}
+	tt.AppendFooter(table.Row{"Total", len(r.players), totalHeroes, ""})
+
+	pt := table.NewWriter()
+	pt.SetStyle(table.StyleColoredBright)
+	pt.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 8, Align: text.AlignRight},
+		{Number: 9, Align: text.AlignRight},
+		{Number: 10, Align: text.AlignRight},
+	})
+	pt.SetTitle("Roster")
+	pt.AppendHeader(table.Row{"#", "Name", "TH", "BK", "AQ", "GW", "RC", "Rushed", "War " + star, "CWL " + star})
+	for _, p := range r.players {
+		pt.AppendRow(table.Row{
+			p.mapPosition, p.name, p.townHall, p.heroes.bk, p.heroes.aq, p.heroes.gw, p.heroes.rc,
+			getPercent(p.completion.Rushed, p.completion.Known), p.warStars, p.cwlStars,
+		})
+		if !p.completion.Known {
+			pt.SetCaption("%s", getCompletionNote())
+		}
+	}
+
+	return ct.Render() + "\n" + tt.Render() + "\n" + pt.Render()
+}
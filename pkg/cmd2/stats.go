@@ -0,0 +1,237 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// attackStats are the totals for a set of attacks
+type attackStats struct {
+	attacks     int // Number of attacks made
+	threeStars  int // Number of attacks that earned three stars
+	stars       int // Number of stars earned
+	destruction int // Total destruction percentage of the attacks
+}
+
+// memberStats are a member's attack statistics over a set of wars
+type memberStats struct {
+	name      string      // Name of the member
+	tag       string      // Tag of the member
+	townHall  int         // Town hall level in the most recent war
+	wars      int         // Number of wars the member was in
+	available int         // Number of attacks available to the member
+	total     attackStats // All attacks by the member
+	equal     attackStats // Attacks against the same town hall level
+	higher    attackStats // Attacks against a higher town hall level
+	lower     attackStats // Attacks against a lower town hall level
+}
+
+// memberStatsList is the list of member statistics for a clan
+type memberStatsList struct {
+	clanName string         // Name of the clan
+	wars     int            // Number of wars the statistics cover
+	members  []*memberStats // Statistics for each member
+}
+
+// memberStatsSorts are the columns the member statistics may be sorted by, with the best first
+var memberStatsSorts = map[string]func(a, b *memberStats) bool{
+	"name":        func(a, b *memberStats) bool { return strings.ToLower(a.name) < strings.ToLower(b.name) },
+	"th":          func(a, b *memberStats) bool { return a.townHall > b.townHall },
+	"wars":        func(a, b *memberStats) bool { return a.wars > b.wars },
+	"attacks":     func(a, b *memberStats) bool { return a.total.attacks > b.total.attacks },
+	"missed":      func(a, b *memberStats) bool { return a.available-a.total.attacks > b.available-b.total.attacks },
+	"hitrate":     func(a, b *memberStats) bool { return a.total.hitRate() > b.total.hitRate() },
+	"stars":       func(a, b *memberStats) bool { return a.total.averageStars() > b.total.averageStars() },
+	"destruction": func(a, b *memberStats) bool { return a.total.averageDestruction() > b.total.averageDestruction() },
+	"equal":       func(a, b *memberStats) bool { return a.equal.hitRate() > b.equal.hitRate() },
+	"higher":      func(a, b *memberStats) bool { return a.higher.hitRate() > b.higher.hitRate() },
+	"lower":       func(a, b *memberStats) bool { return a.lower.hitRate() > b.lower.hitRate() },
+}
+
+// add adds an attack to the totals
+func (s *attackStats) add(stars int, destruction int) {
+	s.attacks++
+	s.stars += stars
+	s.destruction += destruction
+	if stars == 3 {
+		s.threeStars++
+	}
+}
+
+// hitRate returns the percentage of attacks that earned three stars
+func (s attackStats) hitRate() float64 {
+	if s.attacks == 0 {
+		return 0
+	}
+	return float64(s.threeStars) * 100 / float64(s.attacks)
+}
+
+// averageStars returns the average number of stars per attack
+func (s attackStats) averageStars() float64 {
+	if s.attacks == 0 {
+		return 0
+	}
+	return float64(s.stars) / float64(s.attacks)
+}
+
+// averageDestruction returns the average destruction percentage per attack
+func (s attackStats) averageDestruction() float64 {
+	if s.attacks == 0 {
+		return 0
+	}
+	return float64(s.destruction) / float64(s.attacks)
+}
+
+// getMemberStats totals the attacks made by the clan's members in the wars that have ended, keyed by
+// the tag of the member.
+func getMemberStats(wars []archive.War) map[string]*memberStats {
+	stats := make(map[string]*memberStats)
+	for _, w := range wars {
+		if w.State() != "warEnded" {
+			continue
+		}
+		cw := w.ClanWar()
+
+		// Get the town hall level of each defender
+		defenderTH := make(map[string]int)
+		for _, m := range cw.Opponent.Members {
+			defenderTH[m.Tag] = m.TownhallLevel
+		}
+
+		for _, m := range cw.Clan.Members {
+			s, ok := stats[m.Tag]
+			if !ok {
+				s = &memberStats{tag: m.Tag}
+				stats[m.Tag] = s
+			}
+
+			// Wars are oldest first, so the latest name and town hall win
+			s.name = m.Name
+			s.townHall = m.TownhallLevel
+			s.wars++
+			s.available += w.AttacksPerMember()
+
+			for _, a := range m.Attacks {
+				s.total.add(a.Stars, a.DestructionPercentage)
+
+				// An attack on a defender missing from the snapshot can't be compared by town hall
+				th, ok := defenderTH[a.DefenderTag]
+				switch {
+				case !ok || th == 0:
+					continue
+				case th == m.TownhallLevel:
+					s.equal.add(a.Stars, a.DestructionPercentage)
+				case th > m.TownhallLevel:
+					s.higher.add(a.Stars, a.DestructionPercentage)
+				default:
+					s.lower.add(a.Stars, a.DestructionPercentage)
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// StatsMembers gets the attack statistics for each member over the archived wars
+func StatsMembers(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	from, to, err := getDateRange(c)
+	if err != nil {
+		return err
+	}
+	sortBy := strings.ToLower(c.String("sort"))
+	less, ok := memberStatsSorts[sortBy]
+	if !ok {
+		err := fmt.Errorf("invalid sort column %q", sortBy)
+		fmt.Println(err)
+		return err
+	}
+
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	wars, err := a.Wars(tag, from, to)
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+
+	list := memberStatsList{}
+	for _, w := range wars {
+		if w.State() == "warEnded" {
+			list.wars++
+			list.clanName = w.ClanWar().Clan.Name
+		}
+	}
+	for _, s := range getMemberStats(wars) {
+		list.members = append(list.members, s)
+	}
+
+	// Sort by the requested column, then by name
+	reverse := c.Bool("reverse")
+	sort.Slice(list.members, func(i, j int) bool {
+		a, b := list.members[i], list.members[j]
+		if less(a, b) != less(b, a) {
+			return less(a, b) != reverse
+		}
+		return strings.ToLower(a.name) < strings.ToLower(b.name)
+	})
+
+	fmt.Println(list)
+
+	return nil
+}
+
+// getHitRate returns a string representation of the three star rate for a set of attacks
+func getHitRate(s attackStats) string {
+	if s.attacks == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% (%d)", s.hitRate(), s.attacks)
+}
+
+// String returns a string representation of the member statistics
+func (l memberStatsList) String() string {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 6, Align: text.AlignRight},
+		{Number: 7, Align: text.AlignRight},
+		{Number: 8, Align: text.AlignRight},
+		{Number: 9, Align: text.AlignRight},
+		{Number: 10, Align: text.AlignRight},
+		{Number: 11, Align: text.AlignRight},
+	})
+
+	t.SetTitle(l.clanName + " attack statistics over " + strconv.Itoa(l.wars) + " wars")
+	t.AppendHeader(table.Row{"#", "Name", "TH", "Wars", "Attacks", threestar, "Avg " + star, "Avg %", "Equal", "Higher", "Lower"})
+	for i, m := range l.members {
+		attacks := strconv.Itoa(m.total.attacks) + "/" + strconv.Itoa(m.available)
+		t.AppendRow(table.Row{
+			i + 1, m.name, m.townHall, m.wars, attacks,
+			fmt.Sprintf("%.0f%%", m.total.hitRate()),
+			fmt.Sprintf("%.2f", m.total.averageStars()),
+			fmt.Sprintf("%.1f", m.total.averageDestruction()),
+			getHitRate(m.equal), getHitRate(m.higher), getHitRate(m.lower),
+		})
+	}
+
+	return t.Render()
+}
@@ -0,0 +1,70 @@
+package cmd2
+
+import (
+	"testing"
+
+	"github.com/gsow-swc/coc/pkg/archive"
+	"github.com/gsow-swc/coc/pkg/query/response"
+)
+
+// testAttack returns an attack on the defender
+func testAttack(defender string, stars int, destruction int) response.ClanWarAttack {
+	return response.ClanWarAttack{DefenderTag: defender, Stars: stars, DestructionPercentage: destruction}
+}
+
+func TestGetMemberStats(t *testing.T) {
+	war := &response.ClanWar{
+		State:                "warEnded",
+		PreparationStartTime: "20240101T000000.000Z",
+		Clan: response.ClanWarTeam{Tag: "#CLAN", Members: []response.ClanWarMember{
+			{Tag: "#A", Name: "a", TownhallLevel: 14, Attacks: []response.ClanWarAttack{
+				testAttack("#X", 3, 100), // equal
+				testAttack("#Y", 2, 80),  // higher
+			}},
+			{Tag: "#B", Name: "b", TownhallLevel: 14, Attacks: []response.ClanWarAttack{
+				testAttack("#Z", 3, 100),      // lower
+				testAttack("#MISSING", 1, 40), // unknown town hall
+			}},
+		}},
+		Opponent: response.ClanWarTeam{Tag: "#OPP", Members: []response.ClanWarMember{
+			{Tag: "#X", TownhallLevel: 14},
+			{Tag: "#Y", TownhallLevel: 15},
+			{Tag: "#Z", TownhallLevel: 12},
+		}},
+	}
+	inWar := *war
+	inWar.State = "inWar"
+	inWar.PreparationStartTime = "20240201T000000.000Z"
+	wars := []archive.War{
+		{ClanTag: "#CLAN", War: war},
+		{ClanTag: "#CLAN", War: &inWar},
+	}
+
+	stats := getMemberStats(wars)
+	tests := []struct {
+		tag                   string
+		attacks, available    int
+		threeStars            int
+		equal, higher, lower  int
+		averageStars, average float64
+	}{
+		{"#A", 2, 2, 1, 1, 1, 0, 2.5, 90},
+		{"#B", 2, 2, 1, 0, 0, 1, 2, 70},
+	}
+	for _, tt := range tests {
+		s := stats[tt.tag]
+		if s == nil {
+			t.Fatalf("no stats for %s", tt.tag)
+		}
+		if s.wars != 1 || s.total.attacks != tt.attacks || s.available != tt.available || s.total.threeStars != tt.threeStars {
+			t.Errorf("%s: wars %d, attacks %d/%d, three stars %d", tt.tag, s.wars, s.total.attacks, s.available, s.total.threeStars)
+		}
+		if s.equal.attacks != tt.equal || s.higher.attacks != tt.higher || s.lower.attacks != tt.lower {
+			t.Errorf("%s: equal %d, higher %d, lower %d, want %d, %d, %d", tt.tag,
+				s.equal.attacks, s.higher.attacks, s.lower.attacks, tt.equal, tt.higher, tt.lower)
+		}
+		if s.total.averageStars() != tt.averageStars || s.total.averageDestruction() != tt.average {
+			t.Errorf("%s: average stars %v, destruction %v", tt.tag, s.total.averageStars(), s.total.averageDestruction())
+		}
+	}
+}
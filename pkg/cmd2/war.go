@@ -42,6 +42,7 @@ type warSummaryClan struct {
 type warMap struct {
 	endTime  time.Time  // The time the war ends, or ended
 	teamSize int        // Number of members in the war
+	top      int        // Number of strongest members in the strength summary
 	clan     warMapClan // A clan in the war
 	opponent warMapClan // The clan's opponent in the war
 }
@@ -55,15 +56,16 @@ type warMapClan struct {
 
 // warMapRoster a member of a clan in a war.
 type warMapClanMember struct {
-	mapPosition   int    // Map position for the member
-	name          string // Name of the member
-	tag           string // Tag of the member
-	townHall      int    // Town hall level
-	barbarianKing int    // Barbarian King level
-	archerQueen   int    // Archer Queen level
-	grandWarden   int    // Grand warden level
-	royalChampion int    // Royal champion level
-	league        string // League the player is in
+	mapPosition   int     // Map position for the member
+	name          string  // Name of the member
+	tag           string  // Tag of the member
+	townHall      int     // Town hall level
+	barbarianKing int     // Barbarian King level
+	archerQueen   int     // Archer Queen level
+	grandWarden   int     // Grand warden level
+	royalChampion int     // Royal champion level
+	league        string  // League the player is in
+	strength      float64 // War strength estimate
 }
 
 // warStatus gets the status of a war
@@ -215,11 +217,8 @@ func WarCurrent(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	w, err := req.Get()
+	w, err := GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -237,11 +236,8 @@ func WarRoster(c *cli.Context) error {
 		return err
 	}
 
-	req := request.ClanCurrentWar{Tag: tag}
-	war, err := req.Get()
+	war, err := GetCurrentWar(c, tag)
 	if err != nil {
-		log.Error("failed to get the response")
-		fmt.Println(err)
 		return err
 	}
 
@@ -263,6 +259,7 @@ func WarRoster(c *cli.Context) error {
 	wm := warMap{
 		endTime:  getTime(war.EndTime),
 		teamSize: war.TeamSize,
+		top:      c.Int("top"),
 		clan:     warMapClan{name: clan.Name, tag: clan.Tag},
 		opponent: warMapClan{name: opponent.Name, tag: opponent.Tag},
 	}
@@ -286,6 +283,7 @@ func WarRoster(c *cli.Context) error {
 			archerQueen:   heroes.aq,
 			grandWarden:   heroes.gw,
 			royalChampion: heroes.rc,
+			strength:      p.Strength(),
 		}
 
 		wm.clan.members = append(wm.clan.members, cm)
@@ -310,6 +308,7 @@ func WarRoster(c *cli.Context) error {
 			archerQueen:   heroes.aq,
 			grandWarden:   heroes.gw,
 			royalChampion: heroes.rc,
+			strength:      p.Strength(),
 		}
 
 		wm.opponent.members = append(wm.opponent.members, cm)
@@ -366,16 +365,30 @@ func (w warMap) String() string {
 	t := table.NewWriter()
 	t.SetStyle(table.StyleColoredBright)
 	t.SetTitle(w.clan.name + " vs " + w.opponent.name + " (" + w.opponent.tag + ")")
-	t.AppendHeader(table.Row{"#", "Name", "TH", "BK", "AQ", "GW", "RC", "", "Name", "TH", "BK", "AQ", "GW", "RC"})
+	t.AppendHeader(table.Row{"#", "Name", "TH", "BK", "AQ", "GW", "RC", "Str", "", "Name", "TH", "BK", "AQ", "GW", "RC", "Str"})
 	for i := range w.clan.members {
 		m := w.clan.members[i]
 		o := w.opponent.members[i]
-		t.AppendRow(table.Row{i + 1, m.name, m.townHall, m.barbarianKing, m.archerQueen, m.grandWarden, m.royalChampion, "   ", o.name, o.townHall, o.barbarianKing, o.archerQueen, o.grandWarden, o.royalChampion})
+		t.AppendRow(table.Row{i + 1, m.name, m.townHall, m.barbarianKing, m.archerQueen, m.grandWarden, m.royalChampion, fmt.Sprintf("%.0f", m.strength), "   ", o.name, o.townHall, o.barbarianKing, o.archerQueen, o.grandWarden, o.royalChampion, fmt.Sprintf("%.0f", o.strength)})
 	}
 
+	// Compare the strongest members of each clan
+	top := getTopStrength(w.clan.strengths(), w.top)
+	opponentTop := getTopStrength(w.opponent.strengths(), w.top)
+	t.SetCaption("Top %d strength: %s %v vs %s %v", top.count, w.clan.name, top, w.opponent.name, opponentTop)
+
 	return t.Render()
 }
 
+// strengths returns the strength of each member of a clan in a war
+func (wc warMapClan) strengths() []float64 {
+	var strengths []float64
+	for _, m := range wc.members {
+		strengths = append(strengths, m.strength)
+	}
+	return strengths
+}
+
 // String returns a string representation of a clan that is in a war
 func (wc warMapClan) String() string {
 	t := table.NewWriter()
@@ -0,0 +1,322 @@
+package cmd2
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// warAttacksPerMember is the number of attacks each member has in a regular war
+	warAttacksPerMember = 2
+
+	// Rules for assigning targets
+	ruleMirror = "mirror"
+	ruleTop    = "top"
+
+	// Reasons an attacker is assigned a target
+	reasonMirror = "mirror"
+	reasonMatch  = "best match"
+	reasonReach  = "reach"
+)
+
+// warPlanBase is a base in a war, either an attacker in the clan or a target in the opponent
+type warPlanBase struct {
+	mapPosition int      // Map position of the base
+	name        string   // Name of the member
+	tag         string   // Tag of the member
+	townHall    int      // Town hall level
+	heroes      heroes   // Hero levels
+	strength    float64  // War strength estimate
+	left        int      // Attacks left for an attacker
+	stars       int      // Best stars against a target
+	attacked    []string // Tags of the targets an attacker has attacked or is planned to attack
+}
+
+// warPlanCall is an attack assigned to one of the clan's members
+type warPlanCall struct {
+	attacker warPlanBase // The member making the attack
+	target   warPlanBase // The base being attacked
+	reason   string      // Why the attacker was assigned the target
+}
+
+// warPlan is the list of attacks planned for the clan in a war
+type warPlan struct {
+	clanName     string        // Name of the clan
+	opponentName string        // Name of the opponent
+	opponentTag  string        // Tag of the opponent
+	rule         string        // Rule used to assign the targets
+	top          int           // Number of bases at the top of the map for the top rule
+	text         bool          // Output as a plain text call list instead of a table
+	calls        []warPlanCall // Planned attacks, in target map order
+	uncovered    []warPlanBase // Targets no attack could be planned for
+	spare        int           // Attacks left once every target is covered
+}
+
+// hasAttacked returns true if the attacker has already attacked, or is planned to attack, the target
+func (b warPlanBase) hasAttacked(tag string) bool {
+	for _, t := range b.attacked {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// getWarPlanBases gets the bases of a clan in a war, in map order, with the hero levels of each member,
+// where each member has the given number of attacks in the war
+func getWarPlanBases(members []response.ClanWarMember, attacksPerMember int) ([]warPlanBase, error) {
+	sort.Slice(members, func(i, j int) bool { return members[i].MapPosition < members[j].MapPosition })
+
+	var bases []warPlanBase
+	for i, m := range members {
+		req := request.Player{Tag: m.Tag}
+		p, err := req.Get()
+		if err != nil {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return nil, err
+		}
+
+		b := warPlanBase{
+			mapPosition: i + 1,
+			name:        m.Name,
+			tag:         m.Tag,
+			townHall:    m.TownhallLevel,
+			heroes:      getHeroes(p.Heroes),
+			strength:    p.Strength(),
+			left:        attacksPerMember - len(m.Attacks),
+			stars:       m.BestOpponentAttack.Stars,
+		}
+		for _, a := range m.Attacks {
+			b.attacked = append(b.attacked, a.DefenderTag)
+		}
+		bases = append(bases, b)
+	}
+	return bases, nil
+}
+
+// planAttacks assigns attackers to targets.  With the mirror rule each attacker first takes their mirror,
+// and any target that is still uncovered is then given to the weakest attacker who is at least as strong
+// as the target, or the strongest attacker left if there is none.  With the top rule the attackers in
+// the top of the map only attack the targets in the top of the map, and the rest attack the rest, until
+// a group runs out of attacks.  The attackers are updated as attacks are planned.
+func planAttacks(attackers []warPlanBase, targets []warPlanBase, rule string, top int) []warPlanCall {
+	var calls []warPlanCall
+	covered := make(map[string]bool)
+
+	assign := func(a *warPlanBase, t warPlanBase, reason string) {
+		a.left--
+		a.attacked = append(a.attacked, t.tag)
+		covered[t.tag] = true
+		calls = append(calls, warPlanCall{attacker: *a, target: t, reason: reason})
+	}
+
+	// cover gives each uncovered target to the best available attacker in the group
+	cover := func(group func(a warPlanBase, t warPlanBase) bool) {
+		for _, t := range targets {
+			if covered[t.tag] {
+				continue
+			}
+			var best *warPlanBase
+			for i := range attackers {
+				a := &attackers[i]
+				if a.left == 0 || a.hasAttacked(t.tag) || !group(*a, t) {
+					continue
+				}
+				switch {
+				case best == nil:
+					best = a
+				case a.strength >= t.strength && (best.strength < t.strength || a.strength < best.strength):
+					best = a
+				case best.strength < t.strength && a.strength > best.strength:
+					best = a
+				}
+			}
+			if best == nil {
+				continue
+			}
+			reason := reasonMatch
+			if best.strength < t.strength {
+				reason = reasonReach
+			}
+			assign(best, t, reason)
+		}
+	}
+
+	switch rule {
+	case ruleMirror:
+		for i := range attackers {
+			a := &attackers[i]
+			for _, t := range targets {
+				if t.mapPosition == a.mapPosition && a.left > 0 && !a.hasAttacked(t.tag) && !covered[t.tag] {
+					assign(a, t, reasonMirror)
+				}
+			}
+		}
+	case ruleTop:
+		cover(func(a warPlanBase, t warPlanBase) bool { return (a.mapPosition <= top) == (t.mapPosition <= top) })
+	}
+	cover(func(a warPlanBase, t warPlanBase) bool { return true })
+
+	sort.SliceStable(calls, func(i, j int) bool {
+		if calls[i].target.mapPosition != calls[j].target.mapPosition {
+			return calls[i].target.mapPosition < calls[j].target.mapPosition
+		}
+		return calls[i].attacker.mapPosition < calls[j].attacker.mapPosition
+	})
+	return calls
+}
+
+// assign plans the attacks on the targets using the plan's rule, and records the targets no attack could
+// be planned for and the attacks left over
+func (p *warPlan) assign(attackers []warPlanBase, targets []warPlanBase) {
+	attackers = append([]warPlanBase{}, attackers...)
+	p.calls = planAttacks(attackers, targets, p.rule, p.top)
+
+	planned := make(map[string]bool)
+	for _, call := range p.calls {
+		planned[call.target.tag] = true
+	}
+	for _, t := range targets {
+		if !planned[t.tag] {
+			p.uncovered = append(p.uncovered, t)
+		}
+	}
+	for _, a := range attackers {
+		p.spare += a.left
+	}
+}
+
+// WarPlan assigns the clan's attackers to the opponent's bases in the current war, so every base that
+// hasn't been three starred is covered by a planned attack
+func WarPlan(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	rule := strings.ToLower(c.String("rule"))
+	if rule != ruleMirror && rule != ruleTop {
+		err := fmt.Errorf("invalid rule %q, expected %s or %s", rule, ruleMirror, ruleTop)
+		fmt.Println(err)
+		return err
+	}
+	format := strings.ToLower(c.String("format"))
+	if format != "" && format != "table" && format != "text" {
+		err := fmt.Errorf("invalid format %q, expected table or text", format)
+		fmt.Println(err)
+		return err
+	}
+
+	war, attacksPerMember, err := getCurrentWarAttacks(c, tag)
+	if err != nil {
+		return err
+	}
+	if war.State == "notInWar" || war.State == "" {
+		err := fmt.Errorf("clan %s is not in a war", tag)
+		fmt.Println(err)
+		return err
+	}
+
+	// Get the two clans
+	var clan response.ClanWarTeam
+	var opponent response.ClanWarTeam
+	if war.Clan.Tag == tag {
+		clan = war.Clan
+		opponent = war.Opponent
+	} else {
+		clan = war.Opponent
+		opponent = war.Clan
+	}
+
+	attackers, err := getWarPlanBases(clan.Members, attacksPerMember)
+	if err != nil {
+		return err
+	}
+	bases, err := getWarPlanBases(opponent.Members, attacksPerMember)
+	if err != nil {
+		return err
+	}
+	var targets []warPlanBase
+	for _, b := range bases {
+		if b.stars < 3 {
+			targets = append(targets, b)
+		}
+	}
+
+	plan := warPlan{
+		clanName:     clan.Name,
+		opponentName: opponent.Name,
+		opponentTag:  opponent.Tag,
+		rule:         rule,
+		top:          c.Int("top"),
+		text:         format == "text",
+	}
+	plan.assign(attackers, targets)
+
+	fmt.Println(plan)
+
+	return nil
+}
+
+// String returns a string representation of the planned attacks in a war
+func (p warPlan) String() string {
+	rule := p.rule
+	if rule == ruleTop {
+		rule = "top " + strconv.Itoa(p.top)
+	}
+	title := fmt.Sprintf("%s vs %s (%s) call list, %s", p.clanName, p.opponentName, p.opponentTag, rule)
+
+	var notes []string
+	if len(p.uncovered) > 0 {
+		var names []string
+		for _, t := range p.uncovered {
+			names = append(names, strconv.Itoa(t.mapPosition)+". "+t.name)
+		}
+		notes = append(notes, "No attacks left for "+strings.Join(names, ", "))
+	}
+	if p.spare > 0 {
+		notes = append(notes, strconv.Itoa(p.spare)+" attacks left for cleanup")
+	}
+
+	// A plain list that can be posted in the clan chat
+	if p.text {
+		var sb strings.Builder
+		sb.WriteString(title + "\n")
+		for i, call := range p.calls {
+			fmt.Fprintf(&sb, "%d. %s (TH%d) -> %d. %s (TH%d)\n", i+1, call.attacker.name, call.attacker.townHall,
+				call.target.mapPosition, call.target.name, call.target.townHall)
+		}
+		for _, n := range notes {
+			sb.WriteString(n + "\n")
+		}
+		return strings.TrimSuffix(sb.String(), "\n")
+	}
+
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetTitle(title)
+	t.AppendHeader(table.Row{"Call", "#", "Attacker", "TH", "", "#", "Target", "TH", threestar, "Reason"})
+	for i, call := range p.calls {
+		stars := ""
+		if call.target.stars > 0 {
+			stars = getStars(call.target.stars)
+		}
+		t.AppendRow(table.Row{
+			i + 1, call.attacker.mapPosition, call.attacker.name, call.attacker.townHall, "->",
+			call.target.mapPosition, call.target.name, call.target.townHall, stars, call.reason,
+		})
+	}
+	t.SetCaption(strings.Join(notes, "\n"))
+
+	return t.Render()
+}
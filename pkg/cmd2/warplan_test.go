@@ -0,0 +1,123 @@
+package cmd2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testBase returns a base at the given map position, with the tag made from the prefix and the position
+func testBase(prefix string, mapPosition int, strength float64, left int) warPlanBase {
+	tag := prefix + strconv.Itoa(mapPosition)
+	return warPlanBase{mapPosition: mapPosition, name: tag, tag: tag, strength: strength, left: left}
+}
+
+func TestWarPlanAssign(t *testing.T) {
+	attackers := func(left ...int) []warPlanBase {
+		var list []warPlanBase
+		for i, l := range left {
+			list = append(list, testBase("A", i+1, float64(1000-100*i), l))
+		}
+		return list
+	}
+	targets := func(positions ...int) []warPlanBase {
+		var list []warPlanBase
+		for _, p := range positions {
+			list = append(list, testBase("T", p, float64(1000-100*(p-1)), 0))
+		}
+		return list
+	}
+	attacked := func(a warPlanBase, tags ...string) warPlanBase {
+		a.attacked = tags
+		return a
+	}
+
+	tests := []struct {
+		name      string
+		rule      string
+		top       int
+		attackers []warPlanBase
+		targets   []warPlanBase
+		calls     string // Planned calls as attacker>target:reason, in target map order
+		uncovered int
+		spare     int
+	}{
+		{
+			name:      "mirrors",
+			rule:      ruleMirror,
+			attackers: attackers(2, 2, 2),
+			targets:   targets(1, 2, 3),
+			calls:     "A1>T1:mirror A2>T2:mirror A3>T3:mirror",
+			spare:     3,
+		},
+		{
+			name:      "attacker without attacks left",
+			rule:      ruleMirror,
+			attackers: attackers(2, 0, 2),
+			targets:   targets(1, 2, 3),
+			calls:     "A1>T1:mirror A1>T2:best match A3>T3:mirror",
+			spare:     1,
+		},
+		{
+			name:      "target already attacked",
+			rule:      ruleMirror,
+			attackers: []warPlanBase{attacked(testBase("A", 1, 1000, 1), "T1"), testBase("A", 2, 900, 2)},
+			targets:   targets(1, 2),
+			calls:     "A2>T1:reach A2>T2:mirror",
+			spare:     1,
+		},
+		{
+			name:      "only bases that are still targets",
+			rule:      ruleMirror,
+			attackers: attackers(1, 1, 1),
+			targets:   targets(2),
+			calls:     "A2>T2:mirror",
+			spare:     2,
+		},
+		{
+			name:      "top of the map only attacks the top",
+			rule:      ruleTop,
+			top:       2,
+			attackers: attackers(1, 1, 1, 1),
+			targets:   targets(1, 2, 3, 4),
+			calls:     "A1>T1:best match A2>T2:best match A3>T3:best match A4>T4:best match",
+		},
+		{
+			name:      "top group runs out of attacks",
+			rule:      ruleTop,
+			top:       1,
+			attackers: attackers(1, 2, 0),
+			targets:   targets(1, 2, 3),
+			calls:     "A1>T1:best match A2>T2:best match A2>T3:best match",
+		},
+		{
+			name:      "uncovered targets",
+			rule:      ruleMirror,
+			attackers: attackers(1, 0, 0),
+			targets:   targets(1, 2, 3),
+			calls:     "A1>T1:mirror",
+			uncovered: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := warPlan{rule: tt.rule, top: tt.top}
+			p.assign(tt.attackers, tt.targets)
+
+			var calls []string
+			for _, c := range p.calls {
+				calls = append(calls, fmt.Sprintf("%s>%s:%s", c.attacker.tag, c.target.tag, c.reason))
+			}
+			if got := strings.Join(calls, " "); got != tt.calls {
+				t.Errorf("calls = %q, want %q", got, tt.calls)
+			}
+			if len(p.uncovered) != tt.uncovered {
+				t.Errorf("%d targets uncovered, want %d", len(p.uncovered), tt.uncovered)
+			}
+			if p.spare != tt.spare {
+				t.Errorf("%d spare attacks, want %d", p.spare, tt.spare)
+			}
+		})
+	}
+}
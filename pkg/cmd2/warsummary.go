@@ -0,0 +1,325 @@
+package cmd2
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gsow-swc/coc/pkg/query/request"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// warLogPageSize is the number of wars requested in each page of the war log
+	warLogPageSize = 100
+)
+
+// summaryWar is the result of a regular war for a clan
+type summaryWar struct {
+	end           time.Time // The time the war ended
+	teamSize      int       // Number of members in the war
+	result        string    // Result of the war
+	stars         int       // Stars for the clan
+	destruction   float64   // Destruction percentage for the clan
+	opponentLevel int       // Clan level of the opponent
+}
+
+// warRecord is the totals for a set of wars
+type warRecord struct {
+	label       string  // What the wars have in common
+	wins        int     // Number of wars won
+	losses      int     // Number of wars lost
+	ties        int     // Number of wars tied
+	stars       int     // Total stars for the clan
+	destruction float64 // Total destruction percentage for the clan
+}
+
+// warStreaks are the runs of the same result in a list of wars
+type warStreaks struct {
+	current       int    // Length of the current streak
+	currentResult string // Result of the wars in the current streak
+	longestWin    int    // Length of the longest run of wins
+	longestLoss   int    // Length of the longest run of losses
+}
+
+// warSummaryReport is a clan's record over its regular wars
+type warSummaryReport struct {
+	clanName   string      // Name of the clan
+	privateLog bool        // The war log is private, so only the archive was used
+	windows    []warRecord // Records over the most recent wars
+	streaks    warStreaks  // Streaks of wins and losses
+	bySize     []warRecord // Records for each team size
+	byLevel    []warRecord // Records for each opponent clan level
+	byMonth    []warRecord // Records for each month, oldest first
+}
+
+// add adds a war to the record
+func (r *warRecord) add(w summaryWar) {
+	switch w.result {
+	case "win":
+		r.wins++
+	case "lose":
+		r.losses++
+	default:
+		r.ties++
+	}
+	r.stars += w.stars
+	r.destruction += w.destruction
+}
+
+// count returns the number of wars in the record
+func (r warRecord) count() int {
+	return r.wins + r.losses + r.ties
+}
+
+// getSummaryWar returns the result of a regular war for the clan
+func getSummaryWar(w response.ClanWar) summaryWar {
+	return summaryWar{
+		end:           getTime(w.EndTime),
+		teamSize:      w.TeamSize,
+		result:        w.Result,
+		stars:         w.Clan.Stars,
+		destruction:   float64(w.Clan.DestructionPercentage),
+		opponentLevel: w.Opponent.ClanLevel,
+	}
+}
+
+// getWarLog gets every page of the clan's war log, leaving out CWL wars.  The error is a
+// request.PrivateWarLogError if the war log is private.
+func getWarLog(tag string) ([]summaryWar, string, error) {
+	var wars []summaryWar
+
+	// The war log can only be read if the clan has made it public
+	creq := request.Clan{Tag: tag}
+	clan, err := creq.Get()
+	if err != nil {
+		return wars, "", err
+	}
+	clanName := clan.Name
+	if !clan.IsWarLogPublic {
+		return wars, clanName, &request.PrivateWarLogError{Tag: tag}
+	}
+
+	req := request.ClanWars{Tag: tag, Limit: warLogPageSize}
+	for {
+		page, err := req.Get()
+		if err != nil {
+			return wars, clanName, err
+		}
+		for _, w := range page {
+			// CWL wars in the war log have no opponent
+			if w.Opponent.Name == "" || w.Result == "" {
+				continue
+			}
+			wars = append(wars, getSummaryWar(w))
+		}
+		if req.Next == "" || len(page) == 0 {
+			return wars, clanName, nil
+		}
+		req.After = req.Next
+	}
+}
+
+// getRecords groups the wars by a label and returns the record for each label, in the order given by less
+func getRecords(wars []summaryWar, label func(w summaryWar) string, less func(a, b summaryWar) bool) []warRecord {
+	records := make(map[string]*warRecord)
+	first := make(map[string]summaryWar)
+	for _, w := range wars {
+		l := label(w)
+		r, ok := records[l]
+		if !ok {
+			r = &warRecord{label: l}
+			records[l] = r
+			first[l] = w
+		}
+		r.add(w)
+	}
+
+	var list []warRecord
+	for _, r := range records {
+		list = append(list, *r)
+	}
+	sort.Slice(list, func(i, j int) bool { return less(first[list[i].label], first[list[j].label]) })
+	return list
+}
+
+// getStreaks returns the current streak and the longest runs of wins and losses, with the wars newest first
+func getStreaks(wars []summaryWar) warStreaks {
+	var s warStreaks
+	if len(wars) > 0 {
+		s.currentResult = wars[0].result
+		for _, w := range wars {
+			if w.result != s.currentResult {
+				break
+			}
+			s.current++
+		}
+	}
+
+	run := 0
+	for i, w := range wars {
+		if i > 0 && w.result != wars[i-1].result {
+			run = 0
+		}
+		run++
+		switch {
+		case w.result == "win" && run > s.longestWin:
+			s.longestWin = run
+		case w.result == "lose" && run > s.longestLoss:
+			s.longestLoss = run
+		}
+	}
+	return s
+}
+
+// WarSummary gets the clan's record over its regular wars, from every page of the war log and the wars
+// in the archive, with streaks and breakdowns by team size, opponent clan level and month
+func WarSummary(c *cli.Context) error {
+	// Get the tag of the clan
+	tag, err := getTag(c)
+	if err != nil {
+		return err
+	}
+
+	report := warSummaryReport{}
+	wars, clanName, err := getWarLog(tag)
+	if err != nil {
+		var private *request.PrivateWarLogError
+		if !errors.As(err, &private) {
+			log.Error("failed to get the response")
+			fmt.Println(err)
+			return err
+		}
+		log.Info(err)
+		report.privateLog = true
+	}
+	report.clanName = clanName
+
+	// Add the archived wars that are too old for the war log, or all of them if it is private
+	a, err := OpenArchive(c)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	archived, err := a.Wars(tag, time.Time{}, time.Time{})
+	if err != nil {
+		log.Error("failed to read the archive")
+		fmt.Println(err)
+		return err
+	}
+	seen := make(map[time.Time]bool)
+	for _, w := range wars {
+		seen[w.end] = true
+	}
+	for _, aw := range archived {
+		if aw.IsLeagueWar() || aw.State() != "warEnded" {
+			continue
+		}
+		cw := aw.ClanWar()
+		w := getSummaryWar(cw)
+		if seen[w.end] || w.result == "" {
+			continue
+		}
+		seen[w.end] = true
+		wars = append(wars, w)
+		if report.clanName == "" {
+			report.clanName = cw.Clan.Name
+		}
+	}
+	if len(wars) == 0 {
+		err := fmt.Errorf("no regular wars found for clan %s", tag)
+		if report.privateLog {
+			err = fmt.Errorf("the war log for clan %s is private and there are no regular wars in the archive", tag)
+		}
+		fmt.Println(err)
+		return err
+	}
+
+	// Newest first
+	sort.Slice(wars, func(i, j int) bool { return wars[i].end.After(wars[j].end) })
+
+	for _, n := range c.IntSlice("windows") {
+		if n <= 0 || n >= len(wars) {
+			continue
+		}
+		r := warRecord{label: "Last " + strconv.Itoa(n)}
+		for _, w := range wars[:n] {
+			r.add(w)
+		}
+		report.windows = append(report.windows, r)
+	}
+	all := warRecord{label: "All " + strconv.Itoa(len(wars))}
+	for _, w := range wars {
+		all.add(w)
+	}
+	report.windows = append(report.windows, all)
+
+	report.streaks = getStreaks(wars)
+	report.bySize = getRecords(wars,
+		func(w summaryWar) string { return strconv.Itoa(w.teamSize) + "v" + strconv.Itoa(w.teamSize) },
+		func(a, b summaryWar) bool { return a.teamSize > b.teamSize })
+	report.byLevel = getRecords(wars,
+		func(w summaryWar) string { return strconv.Itoa(w.opponentLevel) },
+		func(a, b summaryWar) bool { return a.opponentLevel > b.opponentLevel })
+	report.byMonth = getRecords(wars,
+		func(w summaryWar) string { return w.end.Local().Format("2006-01") },
+		func(a, b summaryWar) bool { return a.end.Before(b.end) })
+
+	fmt.Println(report)
+
+	return nil
+}
+
+// recordTable returns a table of war records
+func recordTable(title string, column string, records []warRecord) table.Writer {
+	t := table.NewWriter()
+	t.SetStyle(table.StyleColoredBright)
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+	})
+
+	t.SetTitle(title)
+	t.AppendHeader(table.Row{column, "Wars", "W-L-T", "Win %", "Avg " + star, "Avg %"})
+	for _, r := range records {
+		n := float64(r.count())
+		record := strconv.Itoa(r.wins) + "-" + strconv.Itoa(r.losses) + "-" + strconv.Itoa(r.ties)
+		t.AppendRow(table.Row{
+			r.label, r.count(), record,
+			fmt.Sprintf("%.0f%%", float64(r.wins)*100/n),
+			fmt.Sprintf("%.1f", float64(r.stars)/n),
+			fmt.Sprintf("%.1f", r.destruction/n),
+		})
+	}
+	return t
+}
+
+// String returns a string representation of a clan's war record
+func (r warSummaryReport) String() string {
+	wt := recordTable(r.clanName+" war record", "Window", r.windows)
+	if r.privateLog {
+		wt.SetCaption("The war log is private, so only wars in the archive are included")
+	}
+
+	st := table.NewWriter()
+	st.SetStyle(table.StyleColoredBright)
+	st.SetTitle("Streaks")
+	st.AppendRow(table.Row{"Current", strconv.Itoa(r.streaks.current) + " " + r.streaks.currentResult})
+	st.AppendRow(table.Row{"Longest win", r.streaks.longestWin})
+	st.AppendRow(table.Row{"Longest loss", r.streaks.longestLoss})
+
+	zt := recordTable("By team size", "Size", r.bySize)
+	lt := recordTable("By opponent clan level", "Level", r.byLevel)
+	mt := recordTable("By month", "Month", r.byMonth)
+
+	return wt.Render() + "\n" + st.Render() + "\n" + zt.Render() + "\n" + lt.Render() + "\n" + mt.Render()
+}
@@ -0,0 +1,38 @@
+package cmd2
+
+import "testing"
+
+func TestGetStreaks(t *testing.T) {
+	wars := func(results ...string) []summaryWar {
+		var list []summaryWar
+		for _, r := range results {
+			list = append(list, summaryWar{result: r})
+		}
+		return list
+	}
+	tests := []struct {
+		name string
+		wars []summaryWar
+		want warStreaks
+	}{
+		{"no wars", nil, warStreaks{}},
+		{"one win", wars("win"), warStreaks{current: 1, currentResult: "win", longestWin: 1}},
+		{
+			"current losses after a longer run of wins",
+			wars("lose", "lose", "win", "win", "win", "tie", "win"),
+			warStreaks{current: 2, currentResult: "lose", longestWin: 3, longestLoss: 2},
+		},
+		{
+			"ties break runs",
+			wars("tie", "win", "win", "tie", "win", "lose", "tie", "lose", "lose", "lose"),
+			warStreaks{current: 1, currentResult: "tie", longestWin: 2, longestLoss: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getStreaks(tt.wars); got != tt.want {
+				t.Errorf("getStreaks = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,18 +1,59 @@
 package config
 
+import (
+	"io/ioutil"
+
+	"github.com/ghodss/yaml"
+)
+
 var (
 	// Data is the configuration data for the application.
 	Data = &config{BaseURL: "https://api.clashofclans.com/v1"}
 )
 
 type config struct {
-	BaseURL        string `json:"base_url"`
-	ResponseFormat string `json:"response_format"`
-	Log            struct {
+	BaseURL        string   `json:"base_url"`
+	ResponseFormat string   `json:"response_format"`
+	Archive        string   `json:"archive"`
+	Clans          []string `json:"clans"`
+	Donations      struct {
+		LeechRatio *float64 `json:"leech_ratio"`
+	} `json:"donations"`
+	CwlBonus struct {
+		Stars       *float64 `json:"stars"`
+		Destruction *float64 `json:"destruction"`
+		Attacks     *float64 `json:"attacks"`
+		TownHall    *float64 `json:"townhall"`
+		Defense     *float64 `json:"defense"`
+	} `json:"cwl_bonus"`
+	CwlPlan struct {
+		MinWars  int      `json:"min_wars"`
+		AlwaysIn []string `json:"always_in"`
+		Bench    []string `json:"bench"`
+	} `json:"cwl_plan"`
+	Missed struct {
+		StrikeLimit *int   `json:"strike_limit"`
+		StrikePer   string `json:"strike_per"`
+	} `json:"missed"`
+	Log struct {
 		Dir   string `json:"dir"`
 		Trial struct {
 			File  string `json:"file"`
 			Level string `json:"level"`
 		} `json:"trial"`
+		Daemon struct {
+			File  string `json:"file"`
+			Level string `json:"level"`
+		} `json:"daemon"`
 	} `json:"log"`
 }
+
+// Load reads the configuration data from a YAML or JSON file.  Settings missing from the file keep
+// their current values.
+func Load(file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(b, Data)
+}
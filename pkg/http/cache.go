@@ -0,0 +1,152 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	// cache is the cache used by all clients.  Caching is disabled when it is nil.
+	cache Cache
+)
+
+// SetCache sets the cache used to store responses received from the server.  Passing nil disables caching.
+func SetCache(c Cache) {
+	cache = c
+}
+
+// Cache stores responses received from a server, keyed by the URL of the request.
+type Cache interface {
+	Get(key string) (CacheEntry, bool) // Get returns the entry stored for the key, if any
+	Set(key string, entry CacheEntry)  // Set stores the entry for the key
+}
+
+// CacheEntry is a response stored in a cache.
+type CacheEntry struct {
+	Body         []byte    `json:"body"`                   // Body of the response
+	Expires      time.Time `json:"expires"`                // Time after which the response must be fetched again
+	ETag         string    `json:"etag,omitempty"`         // ETag used to ask the server if the response changed
+	LastModified string    `json:"lastModified,omitempty"` // Last-Modified time used to ask the server if the response changed
+}
+
+// Fresh returns true if the entry has not yet expired.
+func (e CacheEntry) Fresh() bool {
+	return time.Now().Before(e.Expires)
+}
+
+// MemoryCache is a cache that keeps responses in memory for the life of the process.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+}
+
+// NewMemoryCache returns an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]CacheEntry)}
+}
+
+// Get returns the entry stored for the key, if any.
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+// Set stores the entry for the key.
+func (c *MemoryCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+}
+
+// FileCache is a cache that keeps responses as files in a directory, so they may be reused across runs.
+type FileCache struct {
+	Dir string // Directory holding the cached responses
+}
+
+// NewFileCache returns a cache that stores responses in the given directory, creating it if required.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileCache{Dir: dir}, nil
+}
+
+// path returns the name of the file used to store the entry for the key.
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the entry stored for the key, if any.
+func (c *FileCache) Get(key string) (CacheEntry, bool) {
+	var e CacheEntry
+	b, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return e, false
+	}
+	if err := json.Unmarshal(b, &e); err != nil {
+		log.Debug("failed to parse the cache entry for ", key)
+		return e, false
+	}
+	return e, true
+}
+
+// Set stores the entry for the key.  Failures are logged, as a missing cache entry only costs a request.
+func (c *FileCache) Set(key string, entry CacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn("failed to encode the cache entry for ", key)
+		return
+	}
+
+	// Write to a temporary file and rename it so a concurrent reader never sees a partial entry
+	p := c.path(key)
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		log.Warn("failed to write the cache entry for ", key, ": ", err)
+		return
+	}
+	if err := os.Rename(tmp, p); err != nil {
+		log.Warn("failed to write the cache entry for ", key, ": ", err)
+	}
+}
+
+// maxAge returns how long a response may be cached, based on its Cache-Control and Age headers.
+// A zero duration means the response must not be cached.
+func maxAge(cacheControl string, age string) time.Duration {
+	var d time.Duration
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || secs <= 0 {
+				return 0
+			}
+			d = time.Duration(secs) * time.Second
+		}
+	}
+
+	// Time already spent in upstream caches counts against the max age
+	if secs, err := strconv.Atoi(age); err == nil && secs > 0 {
+		d -= time.Duration(secs) * time.Second
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
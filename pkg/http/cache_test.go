@@ -0,0 +1,68 @@
+package http
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMaxAge(t *testing.T) {
+	tests := []struct {
+		name         string
+		cacheControl string
+		age          string
+		want         time.Duration
+	}{
+		{"no header", "", "", 0},
+		{"max-age", "max-age=120", "", 120 * time.Second},
+		{"mixed case and spaces", "public,  Max-Age=60 ", "", 60 * time.Second},
+		{"age is subtracted", "max-age=120", "20", 100 * time.Second},
+		{"age past max-age", "max-age=10", "30", 0},
+		{"invalid age is ignored", "max-age=10", "soon", 10 * time.Second},
+		{"zero max-age", "max-age=0", "", 0},
+		{"negative max-age", "max-age=-5", "", 0},
+		{"invalid max-age", "max-age=abc", "", 0},
+		{"no-store", "no-store, max-age=60", "", 0},
+		{"no-cache", "max-age=60, no-cache", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maxAge(tt.cacheControl, tt.age); got != tt.want {
+				t.Errorf("maxAge(%q, %q) = %v, want %v", tt.cacheControl, tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coc-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("https://example.com/a"); ok {
+		t.Fatal("Get on an empty cache returned an entry")
+	}
+
+	want := CacheEntry{Body: []byte(`{"a":1}`), Expires: time.Now().Add(time.Minute).Round(0), ETag: `"x"`}
+	c.Set("https://example.com/a", want)
+	got, ok := c.Get("https://example.com/a")
+	if !ok {
+		t.Fatal("Get didn't return the entry that was set")
+	}
+	if string(got.Body) != string(want.Body) || got.ETag != want.ETag || !got.Expires.Equal(want.Expires) {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+	if !got.Fresh() {
+		t.Error("entry expiring in a minute isn't fresh")
+	}
+	if _, ok := c.Get("https://example.com/b"); ok {
+		t.Error("Get returned an entry for a different key")
+	}
+}
@@ -1,10 +1,12 @@
 package http
 
 import (
+	"bytes"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -23,32 +25,155 @@ type Client struct {
 	Headers map[string]string
 }
 
+// setHeaders sets the default and custom headers on a request.
+func (c *Client) setHeaders(req *http.Request) {
+	// Set the default headers
+	for k, v := range defaultHeaders {
+		req.Header.Set(k, v)
+	}
+
+	// Add any custom headers
+	if c.Headers != nil && len(c.Headers) > 0 {
+		for k, v := range c.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+}
+
+// Response is the response received from a server.
+type Response struct {
+	Body        []byte // Body of the response
+	NotModified bool   // The server reported the body is unchanged since it was last received for the same URL
+}
+
+// StatusError is returned when the server responds with an error status code.
+type StatusError struct {
+	StatusCode int    // Status code of the response
+	Status     string // Status of the response
+	Body       []byte // Body of the response, which describes the error
+}
+
+// Error returns a string representation of the error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status=%d, reason=%s", e.StatusCode, e.Status)
+}
+
 // Get sends a request and receives the response from a server.
 func (c *Client) Get(url string) ([]byte, error) {
+	resp, err := c.GetIfModified(url)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// GetIfModified sends a request and receives the response from a server.  When a cache is set, the
+// ETag and Last-Modified values of earlier responses are sent so the server can report that nothing
+// changed, in which case the cached body is returned and NotModified is set in the response.  A body
+// served from the cache before it expires isn't reported as unchanged, as the caller may not have seen it.
+func (c *Client) GetIfModified(url string) (Response, error) {
 	const M = "http.Client.Send"
 	log.Debug(M, " -->")
 	defer log.Debug(M, " <--")
 
+	// Use the cached response if it hasn't expired
+	var entry CacheEntry
+	var cached bool
+	if cache != nil {
+		entry, cached = cache.Get(url)
+		if cached && entry.Fresh() {
+			log.Debug("cached url=", url)
+			return Response{Body: entry.Body}, nil
+		}
+	}
+
 	// Get the http request
 	log.Debug("GET url=", url)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		log.Error("failed to get the http request")
-		return nil, err
+		return Response{}, err
 	}
 
-	// Set the default headers
-	for k, v := range defaultHeaders {
-		req.Header.Set(k, v)
+	c.setHeaders(req)
+
+	// Ask the server to only send the body if it changed since the cached response
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
 	}
 
-	// Add any custom headers
-	if c.Headers != nil && len(c.Headers) > 0 {
-		for k, v := range c.Headers {
-			req.Header.Set(k, v)
+	// Send the request to Clash of Clans and get the response
+	client := &http.Client{Transport: tr}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Error("failed to send the request to CoC")
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	// Nothing changed, so reuse the cached body for as long as the server now allows
+	if resp.StatusCode == http.StatusNotModified && cached {
+		log.Debug("not modified url=", url)
+		entry.Expires = time.Now().Add(maxAge(resp.Header.Get("Cache-Control"), resp.Header.Get("Age")))
+		cache.Set(url, entry)
+		return Response{Body: entry.Body, NotModified: true}, nil
+	}
+
+	// If an error status code was returned by the server, pass the error back to the invoker
+	if resp.StatusCode != 200 {
+		log.Error("failed to send the request to CoC, statusCode=", resp.StatusCode, ", status=", resp.Status)
+		body, _ := ioutil.ReadAll(resp.Body)
+		return Response{}, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
+	}
+
+	// Read the body
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("failed to read the body")
+		return Response{}, err
+	}
+
+	// Cache the response for as long as the server allows, or until it has to be revalidated
+	if cache != nil {
+		e := CacheEntry{
+			Body:         body,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}
+		d := maxAge(resp.Header.Get("Cache-Control"), resp.Header.Get("Age"))
+		e.Expires = time.Now().Add(d)
+		if d > 0 || e.ETag != "" || e.LastModified != "" {
+			cache.Set(url, e)
 		}
 	}
 
+	// fmt.Println(string(body))
+	// All good, so return the response
+	return Response{Body: body}, nil
+}
+
+// Post sends a request with a JSON body and receives the response from a server.  Responses to a
+// POST are never cached.
+func (c *Client) Post(url string, data []byte) ([]byte, error) {
+	const M = "http.Client.Post"
+	log.Debug(M, " -->")
+	defer log.Debug(M, " <--")
+
+	// Get the http request
+	log.Debug("POST url=", url)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(data))
+	if err != nil {
+		log.Error("failed to get the http request")
+		return nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
 	// Send the request to Clash of Clans and get the response
 	client := &http.Client{Transport: tr}
 	resp, err := client.Do(req)
@@ -61,8 +186,8 @@ func (c *Client) Get(url string) ([]byte, error) {
 	// If an error status code was returned by the server, pass the error back to the invoker
 	if resp.StatusCode != 200 {
 		log.Error("failed to send the request to CoC, statusCode=", resp.StatusCode, ", status=", resp.Status)
-		err := fmt.Errorf("status=%d, reason=%s", resp.StatusCode, resp.Status)
-		return nil, err
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}
 	}
 
 	// Read the body
@@ -72,7 +197,5 @@ func (c *Client) Get(url string) ([]byte, error) {
 		return nil, err
 	}
 
-	// fmt.Println(string(body))
-	// All good, so return the response
 	return body, nil
 }
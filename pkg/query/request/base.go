@@ -1,11 +1,17 @@
 package request
 
 import (
+	"encoding/json"
+	"strings"
+
 	"github.com/gsow-swc/coc/pkg/http"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	log "github.com/sirupsen/logrus"
 )
 
 var (
-	token string
+	token  string
+	strict bool
 )
 
 // SetToken sets the token to be used on requests sent to Clash of Clans
@@ -13,20 +19,66 @@ func SetToken(t string) {
 	token = t
 }
 
+// SetStrict sets whether fields in responses that aren't modelled by the response types are logged.
+func SetStrict(s bool) {
+	strict = s
+}
+
 type request interface {
 	getURL() string
 }
 
 // get retrieves the requested URL and return the results as a byte array.
 func get(r request) ([]byte, error) {
+	body, _, err := getIfModified(r)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// getIfModified retrieves the requested URL and returns the results as a byte array, along with whether
+// the results are unchanged since they were last retrieved.
+func getIfModified(r request) ([]byte, bool, error) {
 	headers := map[string]string{
 		"Authorization": "Bearer " + token,
 	}
 	client := http.Client{Headers: headers}
 	url := r.getURL()
-	body, err := client.Get(url)
+	resp, err := client.GetIfModified(url)
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.Body, resp.NotModified, nil
+}
+
+// post sends the data to the requested URL and returns the results as a byte array.
+func post(r request, data []byte) ([]byte, error) {
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+	}
+	client := http.Client{Headers: headers}
+	url := r.getURL()
+	body, err := client.Post(url, data)
 	if err != nil {
 		return nil, err
 	}
 	return body, nil
 }
+
+// decode parses a response into v.  Fields that aren't modelled are kept in the Extra field of the
+// response types and, in strict mode, logged so they may be added.
+func decode(body []byte, v interface{}) error {
+	if !strict {
+		return json.Unmarshal(body, v)
+	}
+
+	unknown, err := response.Decode(body, v)
+	if err != nil {
+		return err
+	}
+	if len(unknown) > 0 {
+		log.Debug("unknown fields in the response: ", strings.Join(unknown, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package request
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	log "github.com/sirupsen/logrus"
+)
+
+// ClanCapitalRaidSeasons is the set of parameters that may be sent to get a clan's capital raid seasons.
+type ClanCapitalRaidSeasons struct {
+	Tag    string // Tag of the clan.
+	Limit  int    // Limit the number of items returned in the response.
+	After  string // Return only items that occur after this marker.
+	Before string // Return only items that occur before this marker.
+}
+
+// getURL returns the request URI that may be sent to get a clan's capital raid seasons.
+func (r *ClanCapitalRaidSeasons) getURL() string {
+	var sb strings.Builder
+	sb.Grow(100)
+
+	// Get the URL to get the requested clan.  The clan tag must be URL encoded to work properly.
+	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString("/clans/")
+	sb.WriteString(url.QueryEscape(r.Tag))
+	sb.WriteString("/capitalraidseasons")
+
+	firstFilter := true
+	if r.Limit > 0 {
+		if firstFilter {
+			sb.WriteString("?")
+		} else {
+			sb.WriteString("&")
+		}
+		sb.WriteString("limit=")
+		sb.WriteString(strconv.Itoa(r.Limit))
+		firstFilter = false
+	}
+	if r.After != "" {
+		if firstFilter {
+			sb.WriteString("?")
+		} else {
+			sb.WriteString("&")
+		}
+		sb.WriteString("after=")
+		sb.WriteString(r.After)
+		firstFilter = false
+	}
+	if r.Before != "" {
+		if firstFilter {
+			sb.WriteString("?")
+		} else {
+			sb.WriteString("&")
+		}
+		sb.WriteString("before=")
+		sb.WriteString(r.Before)
+		firstFilter = false
+	}
+
+	return sb.String()
+}
+
+// Get returns the requested capital raid seasons, the most recent first.
+func (r *ClanCapitalRaidSeasons) Get() ([]response.ClanCapitalRaidSeason, error) {
+	// Get the raid seasons
+	body, err := get(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into an array of raid seasons
+	type respType struct {
+		Items []response.ClanCapitalRaidSeason `json:"items"`
+	}
+	var resp respType
+	err = decode(body, &resp)
+	if err != nil {
+		log.Debug("failed to parse the json response")
+		return nil, err
+	}
+
+	return resp.Items, nil
+}
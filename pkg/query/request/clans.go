@@ -2,11 +2,13 @@ package request
 
 import (
 	"encoding/json"
+	"errors"
 	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/http"
 	"github.com/gsow-swc/coc/pkg/query/response"
 	log "github.com/sirupsen/logrus"
 )
@@ -168,7 +170,7 @@ func (r *Clans) Get() ([]response.Clan, error) {
 		Items []response.Clan `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -179,7 +181,8 @@ func (r *Clans) Get() ([]response.Clan, error) {
 
 // Clan is the parameters that may be sent to get a specific clan.
 type Clan struct {
-	Tag string // Tag of the clan.
+	Tag         string // Tag of the clan.
+	NotModified bool   // Set by Get when the clan is unchanged since it was last retrieved.
 }
 
 // getURL returns the request URI that may be sent to get a specific clan.
@@ -197,29 +200,33 @@ func (r *Clan) getURL() string {
 
 // Get returns the requested clan.
 func (r *Clan) Get() (response.Clan, error) {
+	r.NotModified = false
+
 	// Get the clans
-	body, err := get(r)
+	body, notModified, err := getIfModified(r)
 	if err != nil {
 		return response.Clan{}, err
 	}
 
 	// Parse into a clan
 	var clan response.Clan
-	err = json.Unmarshal(body, &clan)
+	err = decode(body, &clan)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.Clan{}, err
 	}
 
+	r.NotModified = notModified
 	return clan, nil
 }
 
 // ClanMembers are the set of parameters that may be sent to get a list of members of a specific clan.
 type ClanMembers struct {
-	Tag    string // Tag of the clan.
-	Limit  int    // Limit the number of items returned in the response.
-	After  string // Return only items that occur after this marker.
-	Before string // Return only items that occur before this marker.
+	Tag         string // Tag of the clan.
+	Limit       int    // Limit the number of items returned in the response.
+	After       string // Return only items that occur after this marker.
+	Before      string // Return only items that occur before this marker.
+	NotModified bool   // Set by Get when the members are unchanged since they were last retrieved.
 }
 
 // getURL returns the request URI that may be sent to get a list of members of a specific clan.
@@ -270,8 +277,10 @@ func (r *ClanMembers) getURL() string {
 
 // Get returns the requested clan members.
 func (r *ClanMembers) Get() ([]response.ClanMember, error) {
+	r.NotModified = false
+
 	// Get the clan members
-	body, err := get(r)
+	body, notModified, err := getIfModified(r)
 	if err != nil {
 		return nil, err
 	}
@@ -281,21 +290,59 @@ func (r *ClanMembers) Get() ([]response.ClanMember, error) {
 		Items []response.ClanMember `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
+	r.NotModified = notModified
 	return resp.Items, nil
 }
 
 // ClanWars is the set of parameters that may be sent to get a clan's clan war log.
 type ClanWars struct {
-	Tag    string // Tag of the clan.
-	Limit  int    // Limit the number of items returned in the response.
-	After  string // Return only items that occur after this marker.
-	Before string // Return only items that occur before this marker.
+	Tag         string // Tag of the clan.
+	Limit       int    // Limit the number of items returned in the response.
+	After       string // Return only items that occur after this marker.
+	Before      string // Return only items that occur before this marker.
+	NotModified bool   // Set by Get when the war log is unchanged since it was last retrieved.
+	Next        string // Set by Get to the marker for the next page, or empty if this is the last page.
+}
+
+// PrivateWarLogError is returned when a clan's war log or current war can't be retrieved because the
+// clan hasn't made its war log public.
+type PrivateWarLogError struct {
+	Tag string // Tag of the clan.
+}
+
+// Error returns a string representation of the error.
+func (e *PrivateWarLogError) Error() string {
+	return "the war log for clan " + e.Tag + " is private"
+}
+
+// warLogError returns a PrivateWarLogError if the server denied access to a clan's war log because it
+// is private, or else the original error.
+func warLogError(tag string, err error) error {
+	var se *http.StatusError
+	if !errors.As(err, &se) || se.StatusCode != 403 {
+		return err
+	}
+
+	// Access is also denied for an invalid token or IP address, which is reported with a more specific
+	// reason or a message about the authorization, so anything else is taken to be a private war log
+	var reason struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(se.Body, &reason) != nil || reason.Reason != "accessDenied" {
+		return err
+	}
+	message := strings.ToLower(reason.Message)
+	if strings.Contains(message, "authorization") || strings.Contains(message, "token") {
+		return err
+	}
+	return &PrivateWarLogError{Tag: tag}
 }
 
 // getURL returns the request URI that may be sent to get a clan's clan war log.
@@ -344,31 +391,43 @@ func (r *ClanWars) getURL() string {
 	return sb.String()
 }
 
-// Get returns the requested clan members.
+// Get returns a page of the clan's war log, newest first.  Next is set to the marker for the following
+// page, and a PrivateWarLogError is returned if the clan's war log is private.
 func (r *ClanWars) Get() ([]response.ClanWar, error) {
-	// Get the clan members
-	body, err := get(r)
+	r.NotModified = false
+	r.Next = ""
+
+	// Get the war log
+	body, notModified, err := getIfModified(r)
 	if err != nil {
-		return nil, err
+		return nil, warLogError(r.Tag, err)
 	}
 
-	// Parse into an array of clan members
+	// Parse into an array of clan wars
 	type respType struct {
-		Items []response.ClanWar `json:"items"`
+		Items  []response.ClanWar `json:"items"`
+		Paging struct {
+			Cursors struct {
+				After string `json:"after"`
+			} `json:"cursors"`
+		} `json:"paging"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
+	r.NotModified = notModified
+	r.Next = resp.Paging.Cursors.After
 	return resp.Items, nil
 }
 
 // ClanCurrentWar is the set of parameters that may be used to get a clan's current clan war.
 type ClanCurrentWar struct {
-	Tag string // Tag of the clan.
+	Tag         string // Tag of the clan.
+	NotModified bool   // Set by Get when the war is unchanged since it was last retrieved.
 }
 
 // getURL returns the request URI that may be sent to get a clan's current clan war.
@@ -385,28 +444,33 @@ func (r *ClanCurrentWar) getURL() string {
 	return sb.String()
 }
 
-// Get retrieves the current clan war for the specified clan.
+// Get retrieves the current clan war for the specified clan.  A PrivateWarLogError is returned if the
+// clan's war log is private.
 func (r *ClanCurrentWar) Get() (response.ClanWar, error) {
+	r.NotModified = false
+
 	// Get the clan war
-	body, err := get(r)
+	body, notModified, err := getIfModified(r)
 	if err != nil {
-		return response.ClanWar{}, err
+		return response.ClanWar{}, warLogError(r.Tag, err)
 	}
 
 	// Parse into a clan war
 	var cw response.ClanWar
-	err = json.Unmarshal(body, &cw)
+	err = decode(body, &cw)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.ClanWar{}, err
 	}
 
+	r.NotModified = notModified
 	return cw, nil
 }
 
 // ClanWarLeagueGroup is the set of parameters that may be used to retrieve information about a clan's current clan war league group.
 type ClanWarLeagueGroup struct {
-	Tag string // Tag of the clan.
+	Tag         string // Tag of the clan.
+	NotModified bool   // Set by Get when the group is unchanged since it was last retrieved.
 }
 
 // getURL returns the request URI that may be used to retrieve information about a clan's current clan war league group.
@@ -425,26 +489,30 @@ func (r *ClanWarLeagueGroup) getURL() string {
 
 // Get retrieves the current clan war league group for the specified clan.
 func (r *ClanWarLeagueGroup) Get() (response.ClanWarLeagueGroup, error) {
+	r.NotModified = false
+
 	// Get the clan war
-	body, err := get(r)
+	body, notModified, err := getIfModified(r)
 	if err != nil {
 		return response.ClanWarLeagueGroup{}, err
 	}
 
 	// Parse into a clan war
 	var cw response.ClanWarLeagueGroup
-	err = json.Unmarshal(body, &cw)
+	err = decode(body, &cw)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.ClanWarLeagueGroup{}, err
 	}
 
+	r.NotModified = notModified
 	return cw, nil
 }
 
 // ClanWarLeagueWar is the set of parameters that may be used to retrieve information about an individual clan war league war.
 type ClanWarLeagueWar struct {
-	Tag string // Tag of the war.
+	Tag         string // Tag of the war.
+	NotModified bool   // Set by Get when the war is unchanged since it was last retrieved.
 }
 
 // getURL returns the request URI that may be used to retrieve information about an individual clan war league war.
@@ -462,19 +530,22 @@ func (r *ClanWarLeagueWar) getURL() string {
 
 // Get retrieves the current clan war league group for the specified clan.
 func (r *ClanWarLeagueWar) Get() (response.ClanWarLeagueWar, error) {
+	r.NotModified = false
+
 	// Get the clan war
-	body, err := get(r)
+	body, notModified, err := getIfModified(r)
 	if err != nil {
 		return response.ClanWarLeagueWar{}, err
 	}
 
 	// Parse into a clan war
 	var cw response.ClanWarLeagueWar
-	err = json.Unmarshal(body, &cw)
+	err = decode(body, &cw)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.ClanWarLeagueWar{}, err
 	}
 
+	r.NotModified = notModified
 	return cw, nil
 }
@@ -0,0 +1,44 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/config"
+	"github.com/gsow-swc/coc/pkg/query/response"
+	log "github.com/sirupsen/logrus"
+)
+
+// GoldPassSeason is the set of parameters that may be sent to get the current gold pass season.
+type GoldPassSeason struct {
+}
+
+// getURL returns the request URI that may be sent to get the current gold pass season.
+func (r *GoldPassSeason) getURL() string {
+	var sb strings.Builder
+	sb.Grow(100)
+
+	// Get the URL to get the current season
+	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString("/goldpass/seasons/current")
+
+	return sb.String()
+}
+
+// Get retrieves the current gold pass season.
+func (r *GoldPassSeason) Get() (response.GoldPassSeason, error) {
+	// Get the season
+	body, err := get(r)
+	if err != nil {
+		return response.GoldPassSeason{}, err
+	}
+
+	// Parse into a gold pass season
+	var season response.GoldPassSeason
+	err = decode(body, &season)
+	if err != nil {
+		log.Debug("failed to parse the json response")
+		return response.GoldPassSeason{}, err
+	}
+
+	return season, nil
+}
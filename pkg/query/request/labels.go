@@ -1,7 +1,6 @@
 package request
 
 import (
-	"encoding/json"
 	"strconv"
 	"strings"
 
@@ -75,7 +74,7 @@ func (r *ClanLabels) Get() ([]response.Label, error) {
 		Items []response.Label `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -149,7 +148,7 @@ func (r *PlayerLabels) Get() ([]response.Label, error) {
 		Items []response.Label `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
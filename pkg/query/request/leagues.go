@@ -1,7 +1,6 @@
 package request
 
 import (
-	"encoding/json"
 	"net/url"
 	"strconv"
 	"strings"
@@ -76,7 +75,7 @@ func (r *Leagues) Get() ([]response.League, error) {
 		Items []response.League `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -113,7 +112,7 @@ func (r *League) Get() (response.League, error) {
 
 	// Parse into a league
 	var league response.League
-	err = json.Unmarshal(body, &league)
+	err = decode(body, &league)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.League{}, err
@@ -188,7 +187,7 @@ func (r *LeagueSeasons) Get() ([]response.LeagueSeason, error) {
 		Items []response.LeagueSeason `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -263,7 +262,7 @@ func (r *LeagueSeason) Get() (response.LeagueSeason, error) {
 	}
 
 	var ls response.LeagueSeason
-	err = json.Unmarshal(body, &ls)
+	err = decode(body, &ls)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.LeagueSeason{}, err
@@ -337,7 +336,7 @@ func (r *WarLeagues) Get() ([]response.WarLeague, error) {
 		Items []response.WarLeague `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -374,7 +373,7 @@ func (r *WarLeague) Get() (response.WarLeague, error) {
 	}
 
 	var wl response.WarLeague
-	err = json.Unmarshal(body, &wl)
+	err = decode(body, &wl)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.WarLeague{}, err
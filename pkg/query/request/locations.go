@@ -1,7 +1,6 @@
 package request
 
 import (
-	"encoding/json"
 	"net/url"
 	"strconv"
 	"strings"
@@ -76,7 +75,7 @@ func (r *Locations) Get() ([]response.Location, error) {
 		Items []response.Location `json:"items"`
 	}
 	var resp respType
-	err = json.Unmarshal(body, &resp)
+	err = decode(body, &resp)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return nil, err
@@ -113,7 +112,7 @@ func (r *Location) Get() (response.Location, error) {
 
 	// Parse into a location
 	var location response.Location
-	err = json.Unmarshal(body, &location)
+	err = decode(body, &location)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.Location{}, err
@@ -187,7 +186,7 @@ func (r *LocationClanRankings) Get() (response.LocationClanRanking, error) {
 	}
 
 	var lcr response.LocationClanRanking
-	err = json.Unmarshal(body, &lcr)
+	err = decode(body, &lcr)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.LocationClanRanking{}, err
@@ -261,7 +260,7 @@ func (r *LocationPlayerRankings) Get() (response.LocationPlayerRanking, error) {
 	if err != nil {
 		return lpr, err
 	}
-	err = json.Unmarshal(body, &lpr)
+	err = decode(body, &lpr)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return lpr, err
@@ -335,7 +334,7 @@ func (r *LocationClanVersusRankings) Get() (response.LocationClanVersusRanking,
 	if err != nil {
 		return lpr, err
 	}
-	err = json.Unmarshal(body, &lpr)
+	err = decode(body, &lpr)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return lpr, err
@@ -409,7 +408,7 @@ func (r *LocationPlayerVersusRankings) Get() (response.LocationPlayerVersusRanki
 	if err != nil {
 		return lpr, err
 	}
-	err = json.Unmarshal(body, &lpr)
+	err = decode(body, &lpr)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return lpr, err
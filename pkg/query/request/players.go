@@ -12,7 +12,8 @@ import (
 
 // Player contains the parameters to select a given player in SWC
 type Player struct {
-	Tag string
+	Tag         string // Tag of the player.
+	NotModified bool   // Set by Get when the player is unchanged since they were last retrieved.
 }
 
 // getURL returns the request URI that may be sent to get a specific player.
@@ -30,19 +31,68 @@ func (p *Player) getURL() string {
 
 // Get returns the specified player from Clash of Clans.
 func (p *Player) Get() (response.Player, error) {
+	p.NotModified = false
+
 	// Get the player
-	body, err := get(p)
+	body, notModified, err := getIfModified(p)
 	if err != nil {
 		return response.Player{}, err
 	}
 
 	// Parse into a clan
 	var player response.Player
-	err = json.Unmarshal(body, &player)
+	err = decode(body, &player)
 	if err != nil {
 		log.Debug("failed to parse the json response")
 		return response.Player{}, err
 	}
 
+	p.NotModified = notModified
 	return player, nil
 }
+
+// VerifyPlayerToken contains the parameters to verify a player's API token.  The token is shown in
+// the player's in-game settings, so a valid token proves the player owns the account.
+type VerifyPlayerToken struct {
+	Tag   string // Tag of the player.
+	Token string // API token shown in the player's settings.
+}
+
+// getURL returns the request URI that may be sent to verify a player's API token.
+func (r *VerifyPlayerToken) getURL() string {
+	var sb strings.Builder
+	sb.Grow(100)
+
+	// Get the URL to verify the token.  The player tag must be URL encoded to work properly.
+	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString("/players/")
+	sb.WriteString(url.QueryEscape(r.Tag))
+	sb.WriteString("/verifytoken")
+
+	return sb.String()
+}
+
+// Verify checks the API token for the player with Clash of Clans.
+func (r *VerifyPlayerToken) Verify() (response.VerifyToken, error) {
+	// Send the token
+	data, err := json.Marshal(struct {
+		Token string `json:"token"`
+	}{Token: r.Token})
+	if err != nil {
+		return response.VerifyToken{}, err
+	}
+	body, err := post(r, data)
+	if err != nil {
+		return response.VerifyToken{}, err
+	}
+
+	// Parse into a verification status
+	var vt response.VerifyToken
+	err = decode(body, &vt)
+	if err != nil {
+		log.Debug("failed to parse the json response")
+		return response.VerifyToken{}, err
+	}
+
+	return vt, nil
+}
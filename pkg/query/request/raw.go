@@ -0,0 +1,32 @@
+package request
+
+import (
+	"strings"
+
+	"github.com/gsow-swc/coc/pkg/config"
+)
+
+// Raw is the set of parameters that may be sent to get the unparsed response for any API path, so
+// new data may be inspected before the response types model it.
+type Raw struct {
+	Path string // Path relative to the base URL, such as /clans/#2PP/currentwar.
+}
+
+// getURL returns the request URI for the path.  Tags in the path are URL encoded to work properly.
+func (r *Raw) getURL() string {
+	var sb strings.Builder
+	sb.Grow(100)
+
+	sb.WriteString(config.Data.BaseURL)
+	if !strings.HasPrefix(r.Path, "/") {
+		sb.WriteString("/")
+	}
+	sb.WriteString(strings.ReplaceAll(r.Path, "#", "%23"))
+
+	return sb.String()
+}
+
+// Get returns the unparsed response for the path.
+func (r *Raw) Get() ([]byte, error) {
+	return get(r)
+}
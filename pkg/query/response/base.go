@@ -1,5 +1,10 @@
 package response
 
+import (
+	"strings"
+	"time"
+)
+
 // BadgeUrls are the URLs for badges
 type BadgeUrls struct {
 	Small  string `json:"small"`
@@ -12,3 +17,20 @@ type IconUrls struct {
 	Small  string `json:"small"`
 	Medium string `json:"medium"`
 }
+
+// TimeLayout is the layout of the times returned by Clash of Clans
+const TimeLayout = "20060102T150405.000Z"
+
+// ParseTime parses a time returned by Clash of Clans
+func ParseTime(t string) (time.Time, error) {
+	return time.Parse(TimeLayout, t)
+}
+
+// NormalizeTag returns a clan or player tag as Clash of Clans returns it: in upper case with a leading #.
+func NormalizeTag(tag string) string {
+	tag = strings.ToUpper(strings.TrimSpace(tag))
+	if tag != "" && !strings.HasPrefix(tag, "#") {
+		tag = "#" + tag
+	}
+	return tag
+}
@@ -0,0 +1,21 @@
+package response
+
+import "testing"
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"#2PP", "#2PP"},
+		{"2pp", "#2PP"},
+		{"#2pp", "#2PP"},
+		{" #2Pp ", "#2PP"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.tag); got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
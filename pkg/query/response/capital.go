@@ -0,0 +1,113 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClanCapitalRaidSeason is a raid weekend for a clan's capital.
+type ClanCapitalRaidSeason struct {
+	State                   string                        `json:"state"`
+	StartTime               string                        `json:"startTime"`
+	EndTime                 string                        `json:"endTime"`
+	CapitalTotalLoot        int                           `json:"capitalTotalLoot"`
+	RaidsCompleted          int                           `json:"raidsCompleted"`
+	TotalAttacks            int                           `json:"totalAttacks"`
+	EnemyDistrictsDestroyed int                           `json:"enemyDistrictsDestroyed"`
+	OffensiveReward         int                           `json:"offensiveReward"`
+	DefensiveReward         int                           `json:"defensiveReward"`
+	Members                 []ClanCapitalRaidSeasonMember `json:"members,omitempty"`
+	AttackLog               []ClanCapitalRaidSeasonRaid   `json:"attackLog,omitempty"`
+	DefenseLog              []ClanCapitalRaidSeasonRaid   `json:"defenseLog,omitempty"`
+	Extra                   map[string]json.RawMessage    `json:"-"` // Fields that are not yet modelled
+}
+
+// Start returns the time the raid weekend started.
+func (s ClanCapitalRaidSeason) Start() time.Time {
+	t, _ := ParseTime(s.StartTime)
+	return t
+}
+
+// End returns the time the raid weekend ends.
+func (s ClanCapitalRaidSeason) End() time.Time {
+	t, _ := ParseTime(s.EndTime)
+	return t
+}
+
+// String returns a string representation of a raid season
+func (s ClanCapitalRaidSeason) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ClanCapitalRaidSeasonMember is a clan member who attacked during a raid weekend.
+type ClanCapitalRaidSeasonMember struct {
+	Tag                    string                     `json:"tag"`
+	Name                   string                     `json:"name"`
+	Attacks                int                        `json:"attacks"`
+	AttackLimit            int                        `json:"attackLimit"`
+	BonusAttackLimit       int                        `json:"bonusAttackLimit"`
+	CapitalResourcesLooted int                        `json:"capitalResourcesLooted"`
+	Extra                  map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// TotalAttackLimit returns the number of attacks available to the member, including bonus attacks.
+func (m ClanCapitalRaidSeasonMember) TotalAttackLimit() int {
+	return m.AttackLimit + m.BonusAttackLimit
+}
+
+// String returns a string representation of a raid season member
+func (m ClanCapitalRaidSeasonMember) String() string {
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+// ClanCapitalRaidSeasonRaid is a raid against, or by, another clan's capital during a raid weekend.
+type ClanCapitalRaidSeasonRaid struct {
+	Attacker           ClanCapitalRaidSeasonClan       `json:"attacker,omitempty"`
+	Defender           ClanCapitalRaidSeasonClan       `json:"defender,omitempty"`
+	AttackCount        int                             `json:"attackCount"`
+	DistrictCount      int                             `json:"districtCount"`
+	DistrictsDestroyed int                             `json:"districtsDestroyed"`
+	Districts          []ClanCapitalRaidSeasonDistrict `json:"districts,omitempty"`
+	Extra              map[string]json.RawMessage      `json:"-"` // Fields that are not yet modelled
+}
+
+// String returns a string representation of a raid
+func (r ClanCapitalRaidSeasonRaid) String() string {
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// ClanCapitalRaidSeasonClan is the clan on the other side of a raid.
+type ClanCapitalRaidSeasonClan struct {
+	Tag       string                     `json:"tag"`
+	Name      string                     `json:"name"`
+	Level     int                        `json:"level"`
+	BadgeUrls BadgeUrls                  `json:"badgeUrls"`
+	Extra     map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// ClanCapitalRaidSeasonDistrict is a district attacked during a raid.
+type ClanCapitalRaidSeasonDistrict struct {
+	ID                 int                           `json:"id"`
+	Name               string                        `json:"name"`
+	DistrictHallLevel  int                           `json:"districtHallLevel"`
+	DestructionPercent int                           `json:"destructionPercent"`
+	Stars              int                           `json:"stars"`
+	AttackCount        int                           `json:"attackCount"`
+	TotalLooted        int                           `json:"totalLooted"`
+	Attacks            []ClanCapitalRaidSeasonAttack `json:"attacks,omitempty"`
+	Extra              map[string]json.RawMessage    `json:"-"` // Fields that are not yet modelled
+}
+
+// ClanCapitalRaidSeasonAttack is a single attack against a district.
+type ClanCapitalRaidSeasonAttack struct {
+	Attacker struct {
+		Tag  string `json:"tag"`
+		Name string `json:"name"`
+	} `json:"attacker"`
+	DestructionPercent int                        `json:"destructionPercent"`
+	Stars              int                        `json:"stars"`
+	Extra              map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
@@ -33,10 +33,19 @@ type Clan struct {
 		IsCountry     bool   `json:"isCountry"`
 		CountryCode   string `json:"countryCode"`
 	} `json:"location"`
-	Type        string    `json:"type"`
-	Members     int       `json:"members"`
-	Description string    `json:"description"`
-	BadgeUrls   BadgeUrls `json:"badgeUrls"`
+	Type                        string                     `json:"type"`
+	Members                     int                        `json:"members"`
+	Description                 string                     `json:"description"`
+	BadgeUrls                   BadgeUrls                  `json:"badgeUrls"`
+	ChatLanguage                ChatLanguage               `json:"chatLanguage"`
+	IsFamilyFriendly            bool                       `json:"isFamilyFriendly"`
+	RequiredTownhallLevel       int                        `json:"requiredTownhallLevel"`
+	RequiredBuilderBaseTrophies int                        `json:"requiredBuilderBaseTrophies"`
+	ClanBuilderBasePoints       int                        `json:"clanBuilderBasePoints"`
+	ClanCapitalPoints           int                        `json:"clanCapitalPoints"`
+	ClanCapital                 ClanCapital                `json:"clanCapital"`
+	CapitalLeague               League                     `json:"capitalLeague"`
+	Extra                       map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan
@@ -45,6 +54,25 @@ func (c Clan) String() string {
 	return string(b)
 }
 
+// ChatLanguage is the language used in a clan's chat.
+type ChatLanguage struct {
+	ID           int                        `json:"id"`
+	Name         string                     `json:"name"`
+	LanguageCode string                     `json:"languageCode"`
+	Extra        map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// ClanCapital is a clan's capital.
+type ClanCapital struct {
+	CapitalHallLevel int `json:"capitalHallLevel"`
+	Districts        []struct {
+		ID                int    `json:"id"`
+		Name              string `json:"name"`
+		DistrictHallLevel int    `json:"districtHallLevel"`
+	} `json:"districts"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
 // ClanMember is a member of a given clan.
 type ClanMember struct {
 	League struct {
@@ -52,16 +80,18 @@ type ClanMember struct {
 		ID       int      `json:"id"`
 		IconUrls IconUrls `json:"iconUrls"`
 	} `json:"league"`
-	Tag               string `json:"tag"`
-	Name              string `json:"name"`
-	Role              string `json:"role"`
-	ExpLevel          int    `json:"expLevel"`
-	ClanRank          int    `json:"clanRank"`
-	PreviousClanRank  int    `json:"previousClanRank"`
-	Donations         int    `json:"donations"`
-	DonationsReceived int    `json:"donationsReceived"`
-	Trophies          int    `json:"trophies"`
-	VersusTrophies    int    `json:"versusTrophies"`
+	Tag               string                     `json:"tag"`
+	Name              string                     `json:"name"`
+	Role              string                     `json:"role"`
+	ExpLevel          int                        `json:"expLevel"`
+	TownHallLevel     int                        `json:"townHallLevel"`
+	ClanRank          int                        `json:"clanRank"`
+	PreviousClanRank  int                        `json:"previousClanRank"`
+	Donations         int                        `json:"donations"`
+	DonationsReceived int                        `json:"donationsReceived"`
+	Trophies          int                        `json:"trophies"`
+	VersusTrophies    int                        `json:"versusTrophies"`
+	Extra             map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan member
@@ -72,14 +102,15 @@ func (m ClanMember) String() string {
 
 // ClanWar is a given war in a clan's war log.
 type ClanWar struct {
-	State                string      `json:"state,omitempty"`
-	TeamSize             int         `json:"teamSize"`
-	PreparationStartTime string      `json:"preparationStartTime,omitempty"`
-	StartTime            string      `json:"startTime,omitempty"`
-	EndTime              string      `json:"endTime,omitempty"`
-	Result               string      `json:"result,omitempty"`
-	Clan                 ClanWarTeam `json:"clan"`
-	Opponent             ClanWarTeam `json:"opponent"`
+	State                string                     `json:"state,omitempty"`
+	TeamSize             int                        `json:"teamSize"`
+	PreparationStartTime string                     `json:"preparationStartTime,omitempty"`
+	StartTime            string                     `json:"startTime,omitempty"`
+	EndTime              string                     `json:"endTime,omitempty"`
+	Result               string                     `json:"result,omitempty"`
+	Clan                 ClanWarTeam                `json:"clan"`
+	Opponent             ClanWarTeam                `json:"opponent"`
+	Extra                map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war
@@ -90,15 +121,16 @@ func (cw ClanWar) String() string {
 
 // ClanWarTeam is the clan that is participating in the clan war.
 type ClanWarTeam struct {
-	Attacks               int             `json:"attacks"`
-	BadgeUrls             BadgeUrls       `json:"badgeUrls"`
-	ClanLevel             int             `json:"clanLevel"`
-	DestructionPercentage float32         `json:"destructionPercentage"`
-	ExpEarned             int             `json:"expEarned"`
-	Members               []ClanWarMember `json:"members,omitempty"`
-	Name                  string          `json:"name"`
-	Stars                 int             `json:"stars"`
-	Tag                   string          `json:"tag"`
+	Attacks               int                        `json:"attacks"`
+	BadgeUrls             BadgeUrls                  `json:"badgeUrls"`
+	ClanLevel             int                        `json:"clanLevel"`
+	DestructionPercentage float32                    `json:"destructionPercentage"`
+	ExpEarned             int                        `json:"expEarned"`
+	Members               []ClanWarMember            `json:"members,omitempty"`
+	Name                  string                     `json:"name"`
+	Stars                 int                        `json:"stars"`
+	Tag                   string                     `json:"tag"`
+	Extra                 map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war team
@@ -109,13 +141,14 @@ func (cwt ClanWarTeam) String() string {
 
 // ClanWarMember is a member who participated in a clan war.
 type ClanWarMember struct {
-	Attacks            []ClanWarAttack `json:"attacks,omitempty"`
-	BestOpponentAttack ClanWarAttack   `json:"bestOpponentAttack,omitempty"`
-	MapPosition        int             `json:"mapPosition"`
-	Name               string          `json:"name"`
-	OpponentAttacks    int             `json:"opponentAttacks"`
-	Tag                string          `json:"tag"`
-	TownhallLevel      int             `json:"townhallLevel"`
+	Attacks            []ClanWarAttack            `json:"attacks,omitempty"`
+	BestOpponentAttack ClanWarAttack              `json:"bestOpponentAttack,omitempty"`
+	MapPosition        int                        `json:"mapPosition"`
+	Name               string                     `json:"name"`
+	OpponentAttacks    int                        `json:"opponentAttacks"`
+	Tag                string                     `json:"tag"`
+	TownhallLevel      int                        `json:"townhallLevel"`
+	Extra              map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war member
@@ -126,11 +159,12 @@ func (cwm ClanWarMember) String() string {
 
 // ClanWarAttack is an attack made in a clan war.
 type ClanWarAttack struct {
-	Order                 int    `json:"order"`
-	AttackerTag           string `json:"attackerTag"`
-	DefenderTag           string `json:"defenderTag"`
-	Stars                 int    `json:"stars"`
-	DestructionPercentage int    `json:"destructionPercentage"`
+	Order                 int                        `json:"order"`
+	AttackerTag           string                     `json:"attackerTag"`
+	DefenderTag           string                     `json:"defenderTag"`
+	Stars                 int                        `json:"stars"`
+	DestructionPercentage int                        `json:"destructionPercentage"`
+	Extra                 map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war atack
@@ -158,6 +192,7 @@ type ClanWarLeagueGroup struct {
 	Rounds []struct {
 		WarTags []string `json:"warTags"`
 	} `json:"rounds"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war league group
@@ -168,14 +203,15 @@ func (lg ClanWarLeagueGroup) String() string {
 
 // ClanWarLeagueWar is information about an individual clan war league war
 type ClanWarLeagueWar struct {
-	Clan                 ClanWarTeam `json:"clan"`
-	EndTime              string      `json:"endTime"`
-	Opponent             ClanWarTeam `json:"opponent"`
-	PreparationStartTime string      `json:"preparationStartTime"`
-	StartTime            string      `json:"startTime"`
-	State                string      `json:"state"`
-	TeamSize             int         `json:"teamSize"`
-	WarStartTime         string      `json:"warStartTime"`
+	Clan                 ClanWarTeam                `json:"clan"`
+	EndTime              string                     `json:"endTime"`
+	Opponent             ClanWarTeam                `json:"opponent"`
+	PreparationStartTime string                     `json:"preparationStartTime"`
+	StartTime            string                     `json:"startTime"`
+	State                string                     `json:"state"`
+	TeamSize             int                        `json:"teamSize"`
+	WarStartTime         string                     `json:"warStartTime"`
+	Extra                map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan war league war
@@ -0,0 +1,192 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// jsonNames caches the JSON field names of each struct type that keeps unmodelled fields
+	jsonNames sync.Map
+)
+
+// Decode parses the JSON data into v, in the same way as json.Unmarshal, and returns the paths to the
+// fields in the data that v doesn't model, so new fields added by Clash of Clans may be noticed.  The
+// fields themselves are kept in the Extra field of the enclosing struct when it has one, as they are by
+// json.Unmarshal.
+func Decode(data []byte, v interface{}) ([]string, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	walk(reflect.ValueOf(v), data, "", &paths)
+
+	// Each item in a list reports the same fields, so only report each path once
+	seen := make(map[string]bool)
+	var unknown []string
+	for _, p := range paths {
+		if !seen[p] {
+			seen[p] = true
+			unknown = append(unknown, p)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// unmarshalExtra parses the JSON object into v, which must be a pointer to a struct without its own
+// UnmarshalJSON method, and sets extra to the fields in the object that v doesn't model.
+func unmarshalExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	*extra = nil
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		// Not an object, such as null, so there is nothing to keep
+		return nil
+	}
+	names := getJSONNames(reflect.TypeOf(v).Elem())
+	for name, value := range fields {
+		if names[name] || names[strings.ToLower(name)] {
+			continue
+		}
+		if *extra == nil {
+			*extra = make(map[string]json.RawMessage)
+		}
+		(*extra)[name] = value
+	}
+	return nil
+}
+
+// marshalExtra returns the JSON encoding of v, which must be a struct without its own MarshalJSON
+// method, with the fields that aren't modelled merged back in.
+func marshalExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// getJSONNames returns the JSON names of the fields of a struct type, both as given and in lower case,
+// as json.Unmarshal matches names without regard to case.
+func getJSONNames(t reflect.Type) map[string]bool {
+	if names, ok := jsonNames.Load(t); ok {
+		return names.(map[string]bool)
+	}
+
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if sf.PkgPath != "" || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = sf.Name
+		}
+		names[name] = true
+		names[strings.ToLower(name)] = true
+	}
+	jsonNames.Store(t, names)
+	return names
+}
+
+// walk compares the JSON data against the value it was parsed into, recording fields that aren't modelled.
+func walk(v reflect.Value, data json.RawMessage, path string, unknown *[]string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return
+		}
+
+		for name, value := range fields {
+			f, ok := fieldByJSONName(v, name)
+			if !ok {
+				*unknown = append(*unknown, path+"."+name)
+				continue
+			}
+			walk(f, value, path+"."+name, unknown)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			walk(v.Index(i), items[i], path+"[]", unknown)
+		}
+	case reflect.Map:
+		// JSON object keys are strings, so only maps keyed by a string type can be compared
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		var items map[string]json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return
+		}
+		for k, item := range items {
+			value := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+			if value.IsValid() {
+				// Map values can't be set, so only unknown fields are recorded
+				walk(value, item, path+"."+k, unknown)
+			}
+		}
+	}
+}
+
+// fieldByJSONName returns the struct field that the JSON field with the given name is parsed into.
+// As with json.Unmarshal, an exact match is preferred over a case-insensitive match.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	fold := -1
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		jsonName := strings.Split(tag, ",")[0]
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		if jsonName == name {
+			return v.Field(i), true
+		}
+		if fold < 0 && strings.EqualFold(jsonName, name) {
+			fold = i
+		}
+	}
+	if fold >= 0 {
+		return v.Field(fold), true
+	}
+	return reflect.Value{}, false
+}
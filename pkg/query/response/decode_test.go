@@ -0,0 +1,99 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		v       interface{}
+		unknown []string
+	}{
+		{
+			name: "all fields modelled",
+			data: `{"name":"Archer","level":3,"maxLevel":10,"village":"home"}`,
+			v:    &Troop{},
+		},
+		{
+			name:    "unknown top level field",
+			data:    `{"tag":"#ABC","name":"Clan","newField":1}`,
+			v:       &Clan{},
+			unknown: []string{".newField"},
+		},
+		{
+			name:    "unknown fields in nested lists are reported once",
+			data:    `{"clan":{"members":[{"tag":"#A","rank":1},{"tag":"#B","rank":2}]},"opponent":{"attacksUsed":3}}`,
+			v:       &ClanWar{},
+			unknown: []string{".clan.members[].rank", ".opponent.attacksUsed"},
+		},
+		{
+			name: "case insensitive match",
+			data: `{"TAG":"#ABC"}`,
+			v:    &Clan{},
+		},
+		{
+			name:    "map keyed by a string type",
+			data:    `{"a":{"name":"x","extra":true}}`,
+			v:       &map[labelName]Label{},
+			unknown: []string{".a.extra"},
+		},
+		{
+			name: "map keyed by a non-string type",
+			data: `{"1":{"name":"x","extra":true}}`,
+			v:    &map[int]Label{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unknown, err := Decode([]byte(tt.data), tt.v)
+			if err != nil {
+				t.Fatalf("Decode returned %v", err)
+			}
+			if !reflect.DeepEqual(unknown, tt.unknown) {
+				t.Errorf("Decode unknown = %q, want %q", unknown, tt.unknown)
+			}
+		})
+	}
+}
+
+// labelName is a string type used as a map key
+type labelName string
+
+func TestExtraRoundTrip(t *testing.T) {
+	data := `{"state":"warEnded","teamSize":5,"newWarField":"x",` +
+		`"clan":{"tag":"#A","newTeamField":[1,2],"members":[{"tag":"#P","newMemberField":{"a":1},` +
+		`"attacks":[{"stars":3,"duration":90}]}]},"opponent":{"tag":"#B"}}`
+
+	var war ClanWar
+	if err := json.Unmarshal([]byte(data), &war); err != nil {
+		t.Fatal(err)
+	}
+	if string(war.Extra["newWarField"]) != `"x"` {
+		t.Errorf("war Extra = %v", war.Extra)
+	}
+	if string(war.Clan.Extra["newTeamField"]) != `[1,2]` {
+		t.Errorf("team Extra = %v", war.Clan.Extra)
+	}
+	if string(war.Clan.Members[0].Attacks[0].Extra["duration"]) != `90` {
+		t.Errorf("attack Extra = %v", war.Clan.Members[0].Attacks[0].Extra)
+	}
+	if war.Opponent.Extra != nil {
+		t.Errorf("opponent Extra = %v, want nil", war.Opponent.Extra)
+	}
+
+	b, err := json.Marshal(war)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again ClanWar
+	if err := json.Unmarshal(b, &again); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(war, again) {
+		t.Errorf("round trip changed the war:\n got %+v\nwant %+v", again, war)
+	}
+}
@@ -0,0 +1,365 @@
+package response
+
+// The response types keep the fields they don't model in Extra, which these methods fill when the
+// response is parsed and merge back in when it is encoded again, so snapshots don't lose them.  Each
+// method converts to a local type without the methods so json doesn't call them recursively.
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeason) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeason
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeason) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeason
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeasonMember) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeasonMember
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeasonMember) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeasonMember
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeasonRaid) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeasonRaid
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeasonRaid) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeasonRaid
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeasonClan) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeasonClan
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeasonClan) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeasonClan
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeasonDistrict) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeasonDistrict
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeasonDistrict) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeasonDistrict
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapitalRaidSeasonAttack) UnmarshalJSON(data []byte) error {
+	type plain ClanCapitalRaidSeasonAttack
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapitalRaidSeasonAttack) MarshalJSON() ([]byte, error) {
+	type plain ClanCapitalRaidSeasonAttack
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *Clan) UnmarshalJSON(data []byte) error {
+	type plain Clan
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v Clan) MarshalJSON() ([]byte, error) {
+	type plain Clan
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ChatLanguage) UnmarshalJSON(data []byte) error {
+	type plain ChatLanguage
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ChatLanguage) MarshalJSON() ([]byte, error) {
+	type plain ChatLanguage
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanCapital) UnmarshalJSON(data []byte) error {
+	type plain ClanCapital
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanCapital) MarshalJSON() ([]byte, error) {
+	type plain ClanCapital
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanMember) UnmarshalJSON(data []byte) error {
+	type plain ClanMember
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanMember) MarshalJSON() ([]byte, error) {
+	type plain ClanMember
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWar) UnmarshalJSON(data []byte) error {
+	type plain ClanWar
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWar) MarshalJSON() ([]byte, error) {
+	type plain ClanWar
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWarTeam) UnmarshalJSON(data []byte) error {
+	type plain ClanWarTeam
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWarTeam) MarshalJSON() ([]byte, error) {
+	type plain ClanWarTeam
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWarMember) UnmarshalJSON(data []byte) error {
+	type plain ClanWarMember
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWarMember) MarshalJSON() ([]byte, error) {
+	type plain ClanWarMember
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWarAttack) UnmarshalJSON(data []byte) error {
+	type plain ClanWarAttack
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWarAttack) MarshalJSON() ([]byte, error) {
+	type plain ClanWarAttack
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWarLeagueGroup) UnmarshalJSON(data []byte) error {
+	type plain ClanWarLeagueGroup
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWarLeagueGroup) MarshalJSON() ([]byte, error) {
+	type plain ClanWarLeagueGroup
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *ClanWarLeagueWar) UnmarshalJSON(data []byte) error {
+	type plain ClanWarLeagueWar
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v ClanWarLeagueWar) MarshalJSON() ([]byte, error) {
+	type plain ClanWarLeagueWar
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *GoldPassSeason) UnmarshalJSON(data []byte) error {
+	type plain GoldPassSeason
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v GoldPassSeason) MarshalJSON() ([]byte, error) {
+	type plain GoldPassSeason
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *Label) UnmarshalJSON(data []byte) error {
+	type plain Label
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v Label) MarshalJSON() ([]byte, error) {
+	type plain Label
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *League) UnmarshalJSON(data []byte) error {
+	type plain League
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v League) MarshalJSON() ([]byte, error) {
+	type plain League
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LeagueSeason) UnmarshalJSON(data []byte) error {
+	type plain LeagueSeason
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LeagueSeason) MarshalJSON() ([]byte, error) {
+	type plain LeagueSeason
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LeagueSeasonRanking) UnmarshalJSON(data []byte) error {
+	type plain LeagueSeasonRanking
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LeagueSeasonRanking) MarshalJSON() ([]byte, error) {
+	type plain LeagueSeasonRanking
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *WarLeague) UnmarshalJSON(data []byte) error {
+	type plain WarLeague
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v WarLeague) MarshalJSON() ([]byte, error) {
+	type plain WarLeague
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *Location) UnmarshalJSON(data []byte) error {
+	type plain Location
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v Location) MarshalJSON() ([]byte, error) {
+	type plain Location
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LocationClanRanking) UnmarshalJSON(data []byte) error {
+	type plain LocationClanRanking
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LocationClanRanking) MarshalJSON() ([]byte, error) {
+	type plain LocationClanRanking
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LocationPlayerRanking) UnmarshalJSON(data []byte) error {
+	type plain LocationPlayerRanking
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LocationPlayerRanking) MarshalJSON() ([]byte, error) {
+	type plain LocationPlayerRanking
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LocationClanVersusRanking) UnmarshalJSON(data []byte) error {
+	type plain LocationClanVersusRanking
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LocationClanVersusRanking) MarshalJSON() ([]byte, error) {
+	type plain LocationClanVersusRanking
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *LocationPlayerVersusRanking) UnmarshalJSON(data []byte) error {
+	type plain LocationPlayerVersusRanking
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v LocationPlayerVersusRanking) MarshalJSON() ([]byte, error) {
+	type plain LocationPlayerVersusRanking
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *Player) UnmarshalJSON(data []byte) error {
+	type plain Player
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v Player) MarshalJSON() ([]byte, error) {
+	type plain Player
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *Troop) UnmarshalJSON(data []byte) error {
+	type plain Troop
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v Troop) MarshalJSON() ([]byte, error) {
+	type plain Troop
+	return marshalExtra(plain(v), v.Extra)
+}
+
+// UnmarshalJSON parses the JSON data, keeping the fields that aren't modelled in Extra.
+func (v *VerifyToken) UnmarshalJSON(data []byte) error {
+	type plain VerifyToken
+	return unmarshalExtra(data, (*plain)(v), &v.Extra)
+}
+
+// MarshalJSON returns the JSON encoding, including the fields that aren't modelled.
+func (v VerifyToken) MarshalJSON() ([]byte, error) {
+	type plain VerifyToken
+	return marshalExtra(plain(v), v.Extra)
+}
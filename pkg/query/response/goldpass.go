@@ -0,0 +1,31 @@
+package response
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// GoldPassSeason is the current gold pass season.
+type GoldPassSeason struct {
+	StartTime string                     `json:"startTime"`
+	EndTime   string                     `json:"endTime"`
+	Extra     map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// Start returns the time the season started.
+func (s GoldPassSeason) Start() time.Time {
+	t, _ := ParseTime(s.StartTime)
+	return t
+}
+
+// End returns the time the season ends.
+func (s GoldPassSeason) End() time.Time {
+	t, _ := ParseTime(s.EndTime)
+	return t
+}
+
+// String returns a string representation of a gold pass season
+func (s GoldPassSeason) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
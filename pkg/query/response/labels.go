@@ -8,6 +8,7 @@ type Label struct {
 	ID       int    `json:"id"`
 	IconUrls struct {
 	} `json:"iconUrls"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a label
@@ -8,6 +8,7 @@ type League struct {
 	ID       int    `json:"id"`
 	IconUrls struct {
 	} `json:"iconUrls"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a league
@@ -18,7 +19,8 @@ func (l League) String() string {
 
 // LeagueSeason is a league season.
 type LeagueSeason struct {
-	ID string `json:"id"`
+	ID    string                     `json:"id"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a league season
@@ -35,15 +37,16 @@ type LeagueSeasonRanking struct {
 		BadgeUrls struct {
 		} `json:"badgeUrls"`
 	} `json:"clan"`
-	League       League `json:"league"`
-	AttackWins   int    `json:"attackWins"`
-	DefenseWins  int    `json:"defenseWins"`
-	Tag          string `json:"tag"`
-	Name         string `json:"name"`
-	ExpLevel     int    `json:"expLevel"`
-	Rank         int    `json:"rank"`
-	PreviousRank int    `json:"previousRank"`
-	Trophies     int    `json:"trophies"`
+	League       League                     `json:"league"`
+	AttackWins   int                        `json:"attackWins"`
+	DefenseWins  int                        `json:"defenseWins"`
+	Tag          string                     `json:"tag"`
+	Name         string                     `json:"name"`
+	ExpLevel     int                        `json:"expLevel"`
+	Rank         int                        `json:"rank"`
+	PreviousRank int                        `json:"previousRank"`
+	Trophies     int                        `json:"trophies"`
+	Extra        map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a league season
@@ -54,8 +57,9 @@ func (lsr LeagueSeasonRanking) String() string {
 
 // WarLeague is information about a war league.
 type WarLeague struct {
-	Name string `json:"name"`
-	ID   int    `json:"id"`
+	Name  string                     `json:"name"`
+	ID    int                        `json:"id"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a war league
@@ -4,11 +4,12 @@ import "encoding/json"
 
 // Location is information about a location
 type Location struct {
-	LocalizedName string `json:"localizedName"`
-	ID            int    `json:"id"`
-	Name          string `json:"name"`
-	IsCountry     bool   `json:"isCountry"`
-	CountryCode   string `json:"countryCode"`
+	LocalizedName string                     `json:"localizedName"`
+	ID            int                        `json:"id"`
+	Name          string                     `json:"name"`
+	IsCountry     bool                       `json:"isCountry"`
+	CountryCode   string                     `json:"countryCode"`
+	Extra         map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a location
@@ -35,6 +36,7 @@ type LocationClanRanking struct {
 	PreviousRank int    `json:"previousRank"`
 	BadgeUrls    struct {
 	} `json:"badgeUrls"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a location clan ranking
@@ -57,14 +59,15 @@ type LocationPlayerRanking struct {
 		IconUrls struct {
 		} `json:"iconUrls"`
 	} `json:"league"`
-	AttackWins   int    `json:"attackWins"`
-	DefenseWins  int    `json:"defenseWins"`
-	Tag          string `json:"tag"`
-	Name         string `json:"name"`
-	ExpLevel     int    `json:"expLevel"`
-	Rank         int    `json:"rank"`
-	PreviousRank int    `json:"previousRank"`
-	Trophies     int    `json:"trophies"`
+	AttackWins   int                        `json:"attackWins"`
+	DefenseWins  int                        `json:"defenseWins"`
+	Tag          string                     `json:"tag"`
+	Name         string                     `json:"name"`
+	ExpLevel     int                        `json:"expLevel"`
+	Rank         int                        `json:"rank"`
+	PreviousRank int                        `json:"previousRank"`
+	Trophies     int                        `json:"trophies"`
+	Extra        map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a location player ranking
@@ -75,8 +78,9 @@ func (l LocationPlayerRanking) String() string {
 
 // LocationClanVersusRanking is the clan versus ranking for a specific location
 type LocationClanVersusRanking struct {
-	ClanVersusPoints int `json:"clanVersusPoints"`
-	ClanPoints       int `json:"clanPoints"`
+	ClanVersusPoints int                        `json:"clanVersusPoints"`
+	ClanPoints       int                        `json:"clanPoints"`
+	Extra            map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a clan-versus ranking for a location
@@ -93,13 +97,14 @@ type LocationPlayerVersusRanking struct {
 		BadgeUrls struct {
 		} `json:"badgeUrls"`
 	} `json:"clan"`
-	VersusBattleWins int    `json:"versusBattleWins"`
-	Tag              string `json:"tag"`
-	Name             string `json:"name"`
-	ExpLevel         int    `json:"expLevel"`
-	Rank             int    `json:"rank"`
-	PreviousRank     int    `json:"previousRank"`
-	VersusTrophies   int    `json:"versusTrophies"`
+	VersusBattleWins int                        `json:"versusBattleWins"`
+	Tag              string                     `json:"tag"`
+	Name             string                     `json:"name"`
+	ExpLevel         int                        `json:"expLevel"`
+	Rank             int                        `json:"rank"`
+	PreviousRank     int                        `json:"previousRank"`
+	VersusTrophies   int                        `json:"versusTrophies"`
+	Extra            map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
 // String returns a string representation of a player-versus ranking for a location
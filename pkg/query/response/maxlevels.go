@@ -0,0 +1,186 @@
+package response
+
+const (
+	// MaxLevelsVersion is the game update the max level tables were taken from.  Update it whenever the
+	// tables are changed, so it is clear which balance changes they include.
+	MaxLevelsVersion = "2024-11"
+
+	// MaxLevelsTownHall is the highest town hall the max level tables cover.  Completion isn't known for
+	// players above it.
+	MaxLevelsTownHall = 17
+)
+
+// The max level tables give the highest level of each unit for each town hall, starting with town hall 1.
+// A zero means the unit isn't available at that town hall.  Town halls beyond the end of the tables use
+// the max level returned by the API.
+var (
+	// heroMaxLevels are the max levels of the home village heroes
+	heroMaxLevels = map[string][]int{
+		"Barbarian King": {0, 0, 0, 0, 0, 0, 10, 20, 30, 40, 50, 65, 75, 80, 90, 95, 100},
+		"Archer Queen":   {0, 0, 0, 0, 0, 0, 0, 0, 30, 40, 50, 65, 75, 80, 90, 95, 100},
+		"Grand Warden":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 20, 40, 50, 55, 65, 70, 75},
+		"Royal Champion": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 25, 30, 40, 45, 50},
+		"Minion Prince":  {0, 0, 0, 0, 0, 0, 0, 0, 10, 20, 30, 40, 50, 60, 70, 80, 90},
+	}
+
+	// petMaxLevels are the max levels of the hero pets
+	petMaxLevels = map[string][]int{
+		"L.A.S.S.I":     {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 15, 15, 15},
+		"Electro Owl":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 15, 15, 15},
+		"Mighty Yak":    {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 15, 15, 15},
+		"Unicorn":       {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10, 10, 10},
+		"Frosty":        {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10, 10},
+		"Diggy":         {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10, 10},
+		"Poison Lizard": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10, 10},
+		"Phoenix":       {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10, 10},
+		"Spirit Fox":    {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10},
+		"Angry Jelly":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10},
+		"Sneezy":        {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 10, 10},
+	}
+
+	// siegeMachineMaxLevels are the max levels of the siege machines
+	siegeMachineMaxLevels = map[string][]int{
+		"Wall Wrecker":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4, 4, 5, 5, 5},
+		"Battle Blimp":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4, 4, 4, 4, 4},
+		"Stone Slammer":  {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4, 4, 5, 5, 5},
+		"Siege Barracks": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 4, 5, 5, 5},
+		"Log Launcher":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 4, 5, 5, 5},
+		"Flame Flinger":  {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 4, 4, 4},
+		"Battle Drill":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 5, 5},
+		"Troop Launcher": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 4},
+	}
+
+	// troopMaxLevels are the max levels of the home village elixir and dark elixir troops
+	troopMaxLevels = map[string][]int{
+		"Barbarian":         {1, 1, 2, 2, 3, 3, 4, 5, 6, 7, 8, 9, 10, 10, 11, 12, 12},
+		"Archer":            {1, 1, 2, 2, 3, 3, 4, 5, 6, 7, 8, 9, 10, 10, 11, 12, 12},
+		"Giant":             {1, 1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 9, 10, 10, 11, 12, 12},
+		"Goblin":            {0, 1, 2, 2, 3, 3, 4, 5, 6, 6, 7, 7, 8, 8, 8, 9, 9},
+		"Wall Breaker":      {0, 0, 2, 2, 3, 4, 4, 5, 5, 6, 7, 8, 9, 10, 11, 12, 12},
+		"Balloon":           {0, 0, 0, 2, 2, 3, 4, 5, 6, 6, 7, 8, 9, 10, 10, 11, 11},
+		"Wizard":            {0, 0, 0, 0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 11, 12, 12},
+		"Healer":            {0, 0, 0, 0, 0, 1, 2, 3, 4, 4, 5, 6, 7, 7, 8, 9, 9},
+		"Dragon":            {0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 11},
+		"P.E.K.K.A":         {0, 0, 0, 0, 0, 0, 0, 3, 4, 6, 7, 8, 9, 9, 10, 11, 11},
+		"Baby Dragon":       {0, 0, 0, 0, 0, 0, 0, 0, 2, 4, 5, 6, 7, 8, 9, 10, 10},
+		"Miner":             {0, 0, 0, 0, 0, 0, 0, 0, 0, 4, 5, 6, 7, 8, 9, 10, 10},
+		"Electro Dragon":    {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 7, 7},
+		"Yeti":              {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 6},
+		"Dragon Rider":      {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 3, 4, 4},
+		"Electro Titan":     {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 4},
+		"Root Rider":        {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 3},
+		"Minion":            {0, 0, 0, 0, 0, 0, 2, 4, 5, 6, 7, 8, 9, 10, 11, 12, 12},
+		"Hog Rider":         {0, 0, 0, 0, 0, 0, 2, 4, 5, 6, 7, 9, 10, 11, 12, 13, 13},
+		"Valkyrie":          {0, 0, 0, 0, 0, 0, 0, 2, 4, 5, 6, 7, 8, 9, 10, 11, 11},
+		"Golem":             {0, 0, 0, 0, 0, 0, 0, 2, 4, 5, 7, 9, 10, 11, 12, 13, 13},
+		"Witch":             {0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 5, 5, 6, 7, 7},
+		"Lava Hound":        {0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 6, 6, 6, 6},
+		"Bowler":            {0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 7, 8, 8},
+		"Ice Golem":         {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 5, 6, 7, 8, 8, 8},
+		"Headhunter":        {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 3, 3, 3, 3},
+		"Apprentice Warden": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 4, 4},
+	}
+
+	// spellMaxLevels are the max levels of the home village spells
+	spellMaxLevels = map[string][]int{
+		"Lightning Spell":    {0, 0, 0, 0, 4, 4, 5, 6, 7, 8, 9, 9, 9, 9, 10, 11, 11},
+		"Healing Spell":      {0, 0, 0, 0, 0, 3, 4, 5, 6, 7, 7, 7, 8, 8, 9, 10, 10},
+		"Rage Spell":         {0, 0, 0, 0, 0, 0, 4, 5, 5, 5, 5, 6, 6, 6, 6, 6, 6},
+		"Jump Spell":         {0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 3, 3, 4, 4, 5, 5, 5},
+		"Freeze Spell":       {0, 0, 0, 0, 0, 0, 0, 0, 2, 5, 6, 7, 7, 7, 7, 7, 7},
+		"Clone Spell":        {0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 5, 5, 6, 7, 8, 8, 8},
+		"Invisibility Spell": {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 4, 4, 4, 4},
+		"Recall Spell":       {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 5},
+		"Poison Spell":       {0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 6, 7, 8, 9, 10, 10},
+		"Earthquake Spell":   {0, 0, 0, 0, 0, 0, 0, 2, 3, 4, 5, 5, 5, 5, 5, 5, 5},
+		"Haste Spell":        {0, 0, 0, 0, 0, 0, 0, 0, 2, 4, 5, 5, 5, 5, 5, 5, 5},
+		"Skeleton Spell":     {0, 0, 0, 0, 0, 0, 0, 0, 1, 3, 4, 6, 7, 8, 8, 8, 8},
+		"Bat Spell":          {0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 4, 5, 5, 5, 6, 6, 6},
+		"Overgrowth Spell":   {0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 2, 3, 4, 4, 4},
+	}
+)
+
+// Completion is how close a player's home village is to max for the player's town hall.  All values
+// are percentages.
+type Completion struct {
+	Rushed  float64 // Levels missing from the max levels of the previous town hall
+	Offense float64 // Levels of the troops, spells and siege machines compared with their max levels
+	Heroes  float64 // Levels of the heroes and pets compared with their max levels
+	Known   bool    // The max level tables cover the player's town hall
+}
+
+// maxLevelFor returns the max level of a unit at a town hall from a table, and whether the table
+// covers the unit at that town hall
+func maxLevelFor(table map[string][]int, name string, townHall int) (int, bool) {
+	levels, ok := table[name]
+	if !ok || townHall < 1 || townHall > len(levels) {
+		return 0, false
+	}
+	return levels[townHall-1], true
+}
+
+// MaxLevel returns the max level of a home village hero, pet, siege machine, troop or spell at the
+// given town hall.  Units that are not in the max level tables, such as hero equipment and builder base
+// troops, use the max level returned by the API.
+func MaxLevel(u Troop, townHall int) int {
+	if u.Village != HomeVillage {
+		return u.MaxLevel
+	}
+	for _, table := range []map[string][]int{heroMaxLevels, petMaxLevels, siegeMachineMaxLevels, troopMaxLevels, spellMaxLevels} {
+		if max, ok := maxLevelFor(table, u.Name, townHall); ok {
+			// Units can't be above max, so a lower value means the table is out of date
+			if max < u.Level {
+				return u.Level
+			}
+			return max
+		}
+	}
+	return u.MaxLevel
+}
+
+// Completion returns how close the player's home village is to max for the player's town hall.  Units
+// the player hasn't unlocked count as level zero.
+func (p Player) Completion() Completion {
+	levels := make(map[string]int)
+	for _, units := range [][]Troop{p.HomeHeroes(), p.Pets(), p.SiegeMachines(), p.HomeTroops(), p.HomeSpells()} {
+		for _, u := range units {
+			levels[u.Name] = u.Level
+		}
+	}
+
+	// percent returns the levels of the units in the tables as a percentage of their max levels at a town hall
+	percent := func(townHall int, tables ...map[string][]int) (float64, bool) {
+		var total, max int
+		for _, table := range tables {
+			for name := range table {
+				m, ok := maxLevelFor(table, name, townHall)
+				if !ok || m == 0 {
+					continue
+				}
+				max += m
+				if l := levels[name]; l < m {
+					total += l
+				} else {
+					total += m
+				}
+			}
+		}
+		if max == 0 {
+			return 0, false
+		}
+		return float64(total) * 100 / float64(max), true
+	}
+
+	var c Completion
+	all := []map[string][]int{heroMaxLevels, petMaxLevels, siegeMachineMaxLevels, troopMaxLevels, spellMaxLevels}
+	if done, ok := percent(p.TownHallLevel-1, all...); ok {
+		c.Rushed = 100 - done
+	}
+	c.Offense, c.Known = percent(p.TownHallLevel, troopMaxLevels, spellMaxLevels, siegeMachineMaxLevels)
+	// Town halls without heroes have nothing left to upgrade, but town halls past the tables aren't known
+	var ok bool
+	if c.Heroes, ok = percent(p.TownHallLevel, heroMaxLevels, petMaxLevels); !ok && c.Known {
+		c.Heroes = 100
+	}
+	return c
+}
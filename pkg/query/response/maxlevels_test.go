@@ -0,0 +1,98 @@
+package response
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMaxLevelTables(t *testing.T) {
+	for _, table := range []map[string][]int{heroMaxLevels, petMaxLevels, siegeMachineMaxLevels, troopMaxLevels, spellMaxLevels} {
+		for name, levels := range table {
+			if len(levels) != MaxLevelsTownHall {
+				t.Errorf("%s has max levels for %d town halls, want %d", name, len(levels), MaxLevelsTownHall)
+			}
+			for i := 1; i < len(levels); i++ {
+				if levels[i] < levels[i-1] {
+					t.Errorf("%s max level drops at town hall %d", name, i+1)
+				}
+			}
+		}
+	}
+}
+
+// maxedPlayer returns a player at the town hall with every unit in the tables at the max level for
+// the given town hall
+func maxedPlayer(townHall int, levelsFor int) Player {
+	p := Player{TownHallLevel: townHall}
+	add := func(table map[string][]int, units *[]Troop) {
+		for name := range table {
+			if max, ok := maxLevelFor(table, name, levelsFor); ok && max > 0 {
+				*units = append(*units, Troop{Name: name, Level: max, Village: HomeVillage})
+			}
+		}
+	}
+	add(heroMaxLevels, &p.Heroes)
+	add(petMaxLevels, &p.Troops)
+	add(siegeMachineMaxLevels, &p.Troops)
+	add(troopMaxLevels, &p.Troops)
+	add(spellMaxLevels, &p.Spells)
+	return p
+}
+
+func TestCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		player  Player
+		rushed  float64
+		offense float64
+		heroes  float64
+		known   bool
+	}{
+		{"maxed", maxedPlayer(15, 15), 0, 100, 100, true},
+		{"maxed for the previous town hall", maxedPlayer(15, 14), 0, -1, -1, true},
+		{"nothing unlocked", Player{TownHallLevel: 12}, 100, 0, 0, true},
+		{"town hall without heroes", Player{TownHallLevel: 3}, 100, 0, 100, true},
+		{"town hall beyond the tables", maxedPlayer(MaxLevelsTownHall+1, MaxLevelsTownHall), 0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.player.Completion()
+			if c.Known != tt.known {
+				t.Errorf("Known = %v, want %v", c.Known, tt.known)
+			}
+			check := func(what string, got float64, want float64) {
+				if want >= 0 && math.Abs(got-want) > 0.01 {
+					t.Errorf("%s = %.2f, want %.2f", what, got, want)
+				}
+			}
+			check("Rushed", c.Rushed, tt.rushed)
+			check("Offense", c.Offense, tt.offense)
+			check("Heroes", c.Heroes, tt.heroes)
+			if tt.offense < 0 && (c.Offense <= 0 || c.Offense >= 100) {
+				t.Errorf("Offense = %.2f, want between 0 and 100", c.Offense)
+			}
+		})
+	}
+}
+
+func TestMaxLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		unit     Troop
+		townHall int
+		want     int
+	}{
+		{"from the table", Troop{Name: "Archer Queen", Level: 50, MaxLevel: 100, Village: HomeVillage}, 12, 65},
+		{"above the table", Troop{Name: "Archer Queen", Level: 70, MaxLevel: 100, Village: HomeVillage}, 12, 70},
+		{"not in the tables", Troop{Name: "Giant Gauntlet", Level: 5, MaxLevel: 27, Village: HomeVillage}, 15, 27},
+		{"builder base", Troop{Name: "Archer", Level: 5, MaxLevel: 20, Village: "builderBase"}, 15, 20},
+		{"town hall beyond the tables", Troop{Name: "Archer Queen", Level: 95, MaxLevel: 105, Village: HomeVillage}, MaxLevelsTownHall + 1, 105},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaxLevel(tt.unit, tt.townHall); got != tt.want {
+				t.Errorf("MaxLevel = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
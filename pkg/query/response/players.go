@@ -46,17 +46,129 @@ type Player struct {
 		Name     string   `json:"name"`
 		IconUrls IconUrls `json:"iconUrls"`
 	} `json:"labels"`
-	Troops []Troop `json:"troops"`
-	Heroes []Troop `json:"heroes"`
-	Spells []Troop `json:"spells"`
+	Troops                   []Troop `json:"troops"`
+	Heroes                   []Troop `json:"heroes"`
+	HeroEquipment            []Troop `json:"heroEquipment"`
+	Spells                   []Troop `json:"spells"`
+	TownHallWeaponLevel      int     `json:"townHallWeaponLevel"`
+	WarPreference            string  `json:"warPreference"`
+	BuilderBaseTrophies      int     `json:"builderBaseTrophies"`
+	BestBuilderBaseTrophies  int     `json:"bestBuilderBaseTrophies"`
+	ClanCapitalContributions int     `json:"clanCapitalContributions"`
+	BuilderBaseLeague        struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"builderBaseLeague"`
+	PlayerHouse struct {
+		Elements []struct {
+			Type string `json:"type"`
+			ID   int    `json:"id"`
+		} `json:"elements"`
+	} `json:"playerHouse"`
+	Extra map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
 }
 
-// Troop represents a troop, hero or spell in Clash of Clans
+// Troop represents a troop, hero, hero equipment, pet or spell in Clash of Clans
 type Troop struct {
-	Name     string `json:"name"`
-	Level    int    `json:"level"`
-	MaxLevel int    `json:"maxLevel"`
-	Village  string `json:"village"`
+	Name               string                     `json:"name"`
+	Level              int                        `json:"level"`
+	MaxLevel           int                        `json:"maxLevel"`
+	Village            string                     `json:"village"`
+	SuperTroopIsActive bool                       `json:"superTroopIsActive,omitempty"`
+	Extra              map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// IsWarOptedIn returns true if the player has opted in to clan wars.
+func (p Player) IsWarOptedIn() bool {
+	return p.WarPreference == "in"
+}
+
+// Achievement returns the value of the player's achievement with the given name, such as the total stars
+// for "War Hero" or "War League Legend".  False is returned if the player doesn't have the achievement.
+func (p Player) Achievement(name string) (int, bool) {
+	for _, a := range p.Achievements {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return 0, false
+}
+
+// HomeTroops returns the player's home village troops, excluding siege machines, pets and super troops.
+func (p Player) HomeTroops() []Troop {
+	var troops []Troop
+	for _, t := range p.Troops {
+		if t.Village == HomeVillage && !IsSiegeMachine(t.Name) && !IsPet(t.Name) && !IsSuperTroop(t.Name) {
+			troops = append(troops, t)
+		}
+	}
+	return troops
+}
+
+// BuilderBaseTroops returns the player's builder base troops.
+func (p Player) BuilderBaseTroops() []Troop {
+	var troops []Troop
+	for _, t := range p.Troops {
+		if t.Village == BuilderBase {
+			troops = append(troops, t)
+		}
+	}
+	return troops
+}
+
+// SiegeMachines returns the player's siege machines.
+func (p Player) SiegeMachines() []Troop {
+	var troops []Troop
+	for _, t := range p.Troops {
+		if t.Village == HomeVillage && IsSiegeMachine(t.Name) {
+			troops = append(troops, t)
+		}
+	}
+	return troops
+}
+
+// Pets returns the player's hero pets.
+func (p Player) Pets() []Troop {
+	var troops []Troop
+	for _, t := range p.Troops {
+		if t.Village == HomeVillage && IsPet(t.Name) {
+			troops = append(troops, t)
+		}
+	}
+	return troops
+}
+
+// SuperTroops returns the player's super troops.  Active super troops have SuperTroopIsActive set.
+func (p Player) SuperTroops() []Troop {
+	var troops []Troop
+	for _, t := range p.Troops {
+		if t.Village == HomeVillage && IsSuperTroop(t.Name) {
+			troops = append(troops, t)
+		}
+	}
+	return troops
+}
+
+// HomeHeroes returns the player's home village heroes.
+func (p Player) HomeHeroes() []Troop {
+	var heroes []Troop
+	for _, h := range p.Heroes {
+		if h.Village == HomeVillage {
+			heroes = append(heroes, h)
+		}
+	}
+	return heroes
+}
+
+// HomeSpells returns the player's home village spells.
+func (p Player) HomeSpells() []Troop {
+	var spells []Troop
+	for _, s := range p.Spells {
+		if s.Village == HomeVillage {
+			spells = append(spells, s)
+		}
+	}
+	return spells
 }
 
 // String returns a string representation of a player
@@ -64,3 +176,22 @@ func (p Player) String() string {
 	b, _ := json.Marshal(p)
 	return string(b)
 }
+
+// VerifyToken is the result of verifying a player's API token.
+type VerifyToken struct {
+	Tag    string                     `json:"tag"`
+	Token  string                     `json:"token"`
+	Status string                     `json:"status"`
+	Extra  map[string]json.RawMessage `json:"-"` // Fields that are not yet modelled
+}
+
+// Valid returns true if the token belongs to the player.
+func (vt VerifyToken) Valid() bool {
+	return vt.Status == "ok"
+}
+
+// String returns a string representation of a token verification
+func (vt VerifyToken) String() string {
+	b, _ := json.Marshal(vt)
+	return string(b)
+}
@@ -0,0 +1,50 @@
+package response
+
+// StrengthModel returns a war strength estimate for a player, with higher values for stronger players.
+type StrengthModel func(p Player) float64
+
+var (
+	// StrengthEstimate is the model used by Player.Strength.  It may be replaced to change how players are
+	// compared.
+	StrengthEstimate StrengthModel = OffenseStrength
+)
+
+// Strength returns the player's war strength estimate from the StrengthEstimate model.
+func (p Player) Strength() float64 {
+	return StrengthEstimate(p)
+}
+
+// OffenseStrength estimates a player's war strength from the town hall level and how close the heroes,
+// pets, troops, spells and siege machines are to max for the town hall.  Heroes and pets make up half of
+// the offense score and the other units the other half.  A maxed player scores 100 for each town hall
+// level, and each 50% of offense missing costs the same as a town hall level, so a player halfway to max
+// is estimated to be as strong as a maxed player one town hall lower.  Town halls the max level tables
+// don't cover are compared with the max levels returned by the API, since those are the max levels for
+// the highest town hall.
+func OffenseStrength(p Player) float64 {
+	c := p.Completion()
+	offense := (c.Heroes + c.Offense) / 200
+	if !c.Known {
+		offense = apiCompletion(p)
+	}
+	return 100 * (float64(p.TownHallLevel) - 2*(1-offense))
+}
+
+// apiCompletion returns the levels of the player's home village units as a fraction of the max levels
+// returned by the API, with heroes and pets making up half and the other units the other half
+func apiCompletion(p Player) float64 {
+	fraction := func(units ...[]Troop) float64 {
+		var levels, max int
+		for _, set := range units {
+			for _, u := range set {
+				levels += u.Level
+				max += u.MaxLevel
+			}
+		}
+		if max == 0 {
+			return 0
+		}
+		return float64(levels) / float64(max)
+	}
+	return (fraction(p.HomeHeroes(), p.Pets()) + fraction(p.HomeTroops(), p.HomeSpells(), p.SiegeMachines())) / 2
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOffenseStrength(t *testing.T) {
+	// Half way to the max levels returned by the API
+	halfway := Player{
+		TownHallLevel: MaxLevelsTownHall + 1,
+		Heroes:        []Troop{{Name: "Barbarian King", Level: 50, MaxLevel: 100, Village: HomeVillage}},
+		Troops:        []Troop{{Name: "Archer", Level: 6, MaxLevel: 12, Village: HomeVillage}},
+	}
+
+	tests := []struct {
+		name   string
+		player Player
+		want   float64
+	}{
+		{"maxed", maxedPlayer(15, 15), 1500},
+		{"nothing unlocked", Player{TownHallLevel: 12}, 1000},
+		{"town hall beyond the tables", halfway, 100 * float64(MaxLevelsTownHall)},
+		{"town hall beyond the tables with nothing unlocked", Player{TownHallLevel: MaxLevelsTownHall + 1}, 100 * float64(MaxLevelsTownHall-1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OffenseStrength(tt.player); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("OffenseStrength = %.2f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
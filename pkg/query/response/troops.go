@@ -0,0 +1,72 @@
+package response
+
+const (
+	// HomeVillage is the village of troops, heroes and spells used in the home village
+	HomeVillage = "home"
+	// BuilderBase is the village of troops and heroes used in the builder base
+	BuilderBase = "builderBase"
+)
+
+var (
+	// siegeMachines are the names of the siege machines, which the API returns as troops
+	siegeMachines = map[string]bool{
+		"Wall Wrecker":   true,
+		"Battle Blimp":   true,
+		"Stone Slammer":  true,
+		"Siege Barracks": true,
+		"Log Launcher":   true,
+		"Flame Flinger":  true,
+		"Battle Drill":   true,
+		"Troop Launcher": true,
+	}
+
+	// pets are the names of the hero pets, which the API returns as troops
+	pets = map[string]bool{
+		"L.A.S.S.I":     true,
+		"Electro Owl":   true,
+		"Mighty Yak":    true,
+		"Unicorn":       true,
+		"Frosty":        true,
+		"Diggy":         true,
+		"Poison Lizard": true,
+		"Phoenix":       true,
+		"Spirit Fox":    true,
+		"Angry Jelly":   true,
+		"Sneezy":        true,
+	}
+
+	// superTroops are the names of the super troops, which are boosted versions of home village troops
+	superTroops = map[string]bool{
+		"Super Barbarian":    true,
+		"Super Archer":       true,
+		"Super Giant":        true,
+		"Sneaky Goblin":      true,
+		"Super Wall Breaker": true,
+		"Rocket Balloon":     true,
+		"Super Wizard":       true,
+		"Super Dragon":       true,
+		"Inferno Dragon":     true,
+		"Super Minion":       true,
+		"Super Valkyrie":     true,
+		"Super Witch":        true,
+		"Ice Hound":          true,
+		"Super Bowler":       true,
+		"Super Miner":        true,
+		"Super Hog Rider":    true,
+	}
+)
+
+// IsSiegeMachine returns true if the troop with the given name is a siege machine.
+func IsSiegeMachine(name string) bool {
+	return siegeMachines[name]
+}
+
+// IsPet returns true if the troop with the given name is a hero pet.
+func IsPet(name string) bool {
+	return pets[name]
+}
+
+// IsSuperTroop returns true if the troop with the given name is a super troop.
+func IsSuperTroop(name string) bool {
+	return superTroops[name]
+}
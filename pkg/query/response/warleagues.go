@@ -0,0 +1,55 @@
+package response
+
+// warLeagueRules are the rules for the end of a Clan War League season that depend on the war league
+type warLeagueRules struct {
+	promoted int // Number of clans in an 8 clan group promoted to the league above
+	demoted  int // Number of clans in an 8 clan group demoted to the league below
+	bonuses  int // Number of bonus medal rewards, before the extra reward for each war won
+}
+
+var (
+	// warLeagues are the end of season rules for each war league.  Legend League is the highest, so no
+	// clans are promoted from it.
+	warLeagues = map[string]warLeagueRules{
+		"Bronze League III":   {3, 0, 1},
+		"Bronze League II":    {3, 1, 1},
+		"Bronze League I":     {3, 1, 1},
+		"Silver League III":   {2, 1, 2},
+		"Silver League II":    {2, 2, 2},
+		"Silver League I":     {2, 2, 2},
+		"Gold League III":     {2, 2, 2},
+		"Gold League II":      {2, 2, 2},
+		"Gold League I":       {2, 2, 2},
+		"Crystal League III":  {2, 2, 3},
+		"Crystal League II":   {1, 2, 3},
+		"Crystal League I":    {1, 2, 3},
+		"Master League III":   {1, 2, 3},
+		"Master League II":    {1, 2, 3},
+		"Master League I":     {1, 2, 3},
+		"Champion League III": {1, 2, 4},
+		"Champion League II":  {1, 2, 4},
+		"Champion League I":   {1, 2, 4},
+		"Titan League III":    {1, 2, 4},
+		"Titan League II":     {1, 2, 4},
+		"Titan League I":      {1, 2, 4},
+		"Legend League":       {0, 3, 4},
+	}
+)
+
+// WarLeagueMovement returns the number of clans promoted and demoted at the end of a Clan War League
+// season in the war league with the given name.  False is returned if the league isn't known.
+func WarLeagueMovement(league string) (int, int, bool) {
+	r, ok := warLeagues[league]
+	return r.promoted, r.demoted, ok
+}
+
+// WarLeagueBonuses returns the number of bonus medal rewards a clan may hand out at the end of a Clan War
+// League season in the war league with the given name, after winning the given number of wars.  False is
+// returned if the league isn't known.
+func WarLeagueBonuses(league string, wins int) (int, bool) {
+	r, ok := warLeagues[league]
+	if !ok {
+		return 0, false
+	}
+	return r.bonuses + wins, true
+}
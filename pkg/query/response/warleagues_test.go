@@ -0,0 +1,35 @@
+package response
+
+import "testing"
+
+func TestWarLeagueMovement(t *testing.T) {
+	tests := []struct {
+		league   string
+		promoted int
+		demoted  int
+		ok       bool
+	}{
+		{"Bronze League III", 3, 0, true},
+		{"Master League I", 1, 2, true},
+		{"Champion League I", 1, 2, true},
+		{"Titan League I", 1, 2, true},
+		{"Legend League", 0, 3, true},
+		{"Unranked", 0, 0, false},
+	}
+	for _, tt := range tests {
+		promoted, demoted, ok := WarLeagueMovement(tt.league)
+		if promoted != tt.promoted || demoted != tt.demoted || ok != tt.ok {
+			t.Errorf("WarLeagueMovement(%q) = %d, %d, %v, want %d, %d, %v",
+				tt.league, promoted, demoted, ok, tt.promoted, tt.demoted, tt.ok)
+		}
+	}
+}
+
+func TestWarLeagueBonuses(t *testing.T) {
+	if got, ok := WarLeagueBonuses("Titan League II", 5); got != 9 || !ok {
+		t.Errorf("WarLeagueBonuses = %d, %v, want 9, true", got, ok)
+	}
+	if _, ok := WarLeagueBonuses("Unranked", 5); ok {
+		t.Error("WarLeagueBonuses found an unknown league")
+	}
+}